@@ -0,0 +1,321 @@
+package apns
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	sdk "github.com/holicc/push-sdk"
+	"github.com/holicc/push-sdk/http"
+	"github.com/holicc/push-sdk/jwt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ProductionHost = "https://api.push.apple.com"
+	SandboxHost    = "https://api.sandbox.push.apple.com"
+
+	devicePath = "/3/device/"
+
+	maxPayloadSize = 4096
+
+	// APNs 拒绝超过 60 分钟的 token，且 20 分钟内重复刷新会返回 TooManyProviderTokenUpdates
+	minRefreshInterval     = 20 * time.Minute
+	maxRefreshInterval     = 60 * time.Minute
+	defaultRefreshInterval = 50 * time.Minute
+)
+
+type MessageRequest struct {
+	DeviceToken string                 `json:"-"` // 设备 token
+	ApnsId      string                 `json:"-"` // apns-id，为空时由 APNs 生成
+	Priority    int                    `json:"-"` // apns-priority，10 立即发送, 5 省电发送, 1 低优先级
+	PushType    string                 `json:"-"` // apns-push-type，alert、background、voip 等
+	CollapseId  string                 `json:"-"` // apns-collapse-id，不超过64字节
+	Expiration  time.Time              `json:"-"` // apns-expiration，零值表示只尝试发送一次
+	Topic       string                 `json:"-"` // apns-topic，为空时使用 BundleId
+	Aps         Aps                    `json:"aps"`
+	Custom      map[string]interface{} `json:"-"` // 自定义字段，与 aps 同级
+}
+
+type Aps struct {
+	Alert            *Alert `json:"alert,omitempty"`
+	Badge            *int   `json:"badge,omitempty"`
+	Sound            string `json:"sound,omitempty"`
+	ThreadId         string `json:"thread-id,omitempty"`
+	Category         string `json:"category,omitempty"`
+	ContentAvailable int    `json:"content-available,omitempty"`
+	MutableContent   int    `json:"mutable-content,omitempty"`
+}
+
+type Alert struct {
+	Title    string `json:"title,omitempty"`
+	Subtitle string `json:"subtitle,omitempty"`
+	Body     string `json:"body,omitempty"`
+}
+
+type MessageResponse struct {
+	Status       int    `json:"-"`
+	ApnsId       string `json:"-"`
+	ApnsUniqueId string `json:"-"`
+	Reason       string `json:"reason"`    // 失败原因，如 BadDeviceToken
+	Timestamp    int64  `json:"timestamp"` // Unregistered 时 token 失效的时间，毫秒
+}
+
+type TokenInfo struct {
+	TeamId          string
+	KeyId           string
+	Key             *ecdsa.PrivateKey
+	RefreshInterval time.Duration
+
+	Token      string
+	CreateTime time.Time
+}
+
+type client struct {
+	ap         sdk.APNs
+	client     *http.HTTPClient
+	authClient *http.AuthClient
+}
+
+func NewApnsClient(ap sdk.APNs) (*client, error) {
+	if ap.TeamId == "" {
+		return nil, errors.New("team id empty")
+	}
+	if ap.KeyId == "" {
+		return nil, errors.New("key id empty")
+	}
+	if ap.BundleId == "" {
+		return nil, errors.New("bundle id empty")
+	}
+	if ap.AuthKey == "" {
+		return nil, errors.New("auth key empty")
+	}
+	key, err := jwt.ParseECPrivateKey([]byte(ap.AuthKey))
+	if err != nil {
+		return nil, err
+	}
+	SetDefaults(&ap)
+
+	return &client{
+		ap:     ap,
+		client: http.NewHTTP2Client(),
+		authClient: http.NewAuthClient(&TokenInfo{
+			TeamId:          ap.TeamId,
+			KeyId:           ap.KeyId,
+			Key:             key,
+			RefreshInterval: defaultRefreshInterval,
+		}),
+	}, nil
+}
+
+// SetDefaults 为未填写的主机地址按 Sandbox 填充生产或开发环境地址
+func SetDefaults(ap *sdk.APNs) {
+	if ap.Host == "" {
+		ap.Host = ProductionHost
+		if ap.Sandbox {
+			ap.Host = SandboxHost
+		}
+	}
+}
+
+func (c *client) Notify(ctx context.Context, req sdk.MessageRequest) (sdk.MessageResponse, error) {
+	if e := req.Validate(); e != nil {
+		return nil, e
+	}
+	r, ok := req.(*MessageRequest)
+	if !ok {
+		return nil, errors.New("unsupported message request type")
+	}
+
+	token, err := c.authClient.GetAuthToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := r.GetRequestBody()
+	if err != nil {
+		return nil, err
+	}
+
+	topic := r.Topic
+	if topic == "" {
+		topic = c.ap.BundleId
+	}
+	header := []http.HTTPOption{
+		http.SetHeader("Content-Type", "application/json"),
+		http.SetHeader("authorization", "bearer "+token),
+		http.SetHeader("apns-topic", topic),
+	}
+	if r.ApnsId != "" {
+		header = append(header, http.SetHeader("apns-id", r.ApnsId))
+	}
+	if r.Priority != 0 {
+		header = append(header, http.SetHeader("apns-priority", strconv.Itoa(r.Priority)))
+	}
+	if r.PushType != "" {
+		header = append(header, http.SetHeader("apns-push-type", r.PushType))
+	}
+	if r.CollapseId != "" {
+		header = append(header, http.SetHeader("apns-collapse-id", r.CollapseId))
+	}
+	if !r.Expiration.IsZero() {
+		header = append(header, http.SetHeader("apns-expiration", strconv.FormatInt(r.Expiration.Unix(), 10)))
+	}
+
+	resp, err := c.client.Do(ctx, &http.PushRequest{
+		Method: "POST",
+		URL:    strings.TrimRight(c.ap.Host, "/") + devicePath + r.DeviceToken,
+		Body:   data,
+		Header: header,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mr := MessageResponse{
+		Status:       resp.Status,
+		ApnsId:       resp.Header.Get("apns-id"),
+		ApnsUniqueId: resp.Header.Get("apns-unique-id"),
+	}
+	if resp.Status != 200 {
+		if len(resp.Body) > 0 {
+			_ = json.Unmarshal(resp.Body, &mr)
+		}
+		// 失败时同时返回响应，调用方可根据 Reason 与 Timestamp 清理失效的 token
+		return &mr, errors.New(fmt.Sprintf("notify request failed %d %s", resp.Status, mr.Reason))
+	}
+
+	return &mr, nil
+}
+
+func (m *MessageRequest) Validate() error {
+	if m.DeviceToken == "" {
+		return errors.New("device token empty")
+	}
+	if m.Priority != 0 && m.Priority != 1 && m.Priority != 5 && m.Priority != 10 {
+		return errors.New("wrong apns priority")
+	}
+	if len(m.CollapseId) > 64 {
+		return errors.New("collapse id longer than 64 bytes")
+	}
+	if m.PushType == "background" && m.Priority == 10 {
+		return errors.New("background push must not use priority 10")
+	}
+	if _, ok := m.Custom["aps"]; ok {
+		return errors.New("custom payload must not contain aps")
+	}
+	return nil
+}
+
+func (m *MessageRequest) GetRequestBody() ([]byte, error) {
+	payload := make(map[string]interface{}, len(m.Custom)+1)
+	for k, v := range m.Custom {
+		payload[k] = v
+	}
+	payload["aps"] = m.Aps
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxPayloadSize {
+		return nil, errors.New(fmt.Sprintf("payload size %d exceeds %d bytes", len(data), maxPayloadSize))
+	}
+	return data, nil
+}
+
+func (m *MessageResponse) err() error {
+	if m.Status == 200 {
+		return nil
+	}
+	return errors.New(fmt.Sprintf("notify request failed %d %s", m.Status, m.Reason))
+}
+
+func (m *MessageResponse) GetResult() string {
+	return strconv.Itoa(m.Status)
+}
+
+func (m *MessageResponse) GetData() map[string]string {
+	return map[string]string{
+		"apns-id":        m.ApnsId,
+		"apns-unique-id": m.ApnsUniqueId,
+	}
+}
+
+// GenerateToken 使用 .p8 私钥签发 ES256 provider token
+func (t *TokenInfo) GenerateToken() error {
+	now := time.Now()
+	token, err := jwt.SignES256(t.Key, map[string]interface{}{
+		"kid": t.KeyId,
+	}, map[string]interface{}{
+		"iss": t.TeamId,
+		"iat": now.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	t.Token = token
+	t.CreateTime = now
+	return nil
+}
+
+func (t *TokenInfo) TokenRequest() ([]byte, error) {
+	return nil, errors.New("apns provider token is generated locally")
+}
+
+func (t *TokenInfo) ParseResponse([]byte) error {
+	return errors.New("apns provider token is generated locally")
+}
+
+func (t *TokenInfo) SetHeader() []http.HTTPOption {
+	return nil
+}
+
+func (t *TokenInfo) GetAuthUrl() string {
+	return ""
+}
+
+func (t *TokenInfo) GetAuthMethod() string {
+	return ""
+}
+
+func (t *TokenInfo) GetAccessToken() string {
+	return t.Token
+}
+
+func (t *TokenInfo) IsValidate() bool {
+	interval := t.RefreshInterval
+	if interval < minRefreshInterval {
+		interval = minRefreshInterval
+	}
+	if interval >= maxRefreshInterval {
+		interval = defaultRefreshInterval
+	}
+	return t.Token != "" && time.Now().Sub(t.CreateTime) < interval
+}
+
+// FromMessage 将通用消息转换为发送到指定设备 token 的 alert 推送，自定义参数与 aps 同级
+func FromMessage(msg *sdk.Message, deviceToken string) (*MessageRequest, error) {
+	req := &MessageRequest{
+		DeviceToken: deviceToken,
+		PushType:    "alert",
+		Aps: Aps{
+			Alert: &Alert{
+				Title:    msg.Title,
+				Subtitle: msg.SubTitle,
+				Body:     msg.Content,
+			},
+		},
+	}
+	if len(msg.Extra) > 0 {
+		req.Custom = make(map[string]interface{}, len(msg.Extra))
+		for k, v := range msg.Extra {
+			req.Custom[k] = v
+		}
+	}
+	return req, nil
+}
@@ -0,0 +1,228 @@
+package apns
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	sdk "github.com/holicc/push-sdk"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newAuthKey(t *testing.T) (*ecdsa.PrivateKey, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func verifyToken(t *testing.T, pub *ecdsa.PublicKey, token string) map[string]interface{} {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("malformed token %s", token)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || len(sig) != 64 {
+		t.Fatalf("malformed signature %s", parts[2])
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:])
+	if !ecdsa.Verify(pub, digest[:], r, s) {
+		t.Fatal("token signature verify failed")
+	}
+	headerJson, _ := base64.RawURLEncoding.DecodeString(parts[0])
+	var header map[string]interface{}
+	if err := json.Unmarshal(headerJson, &header); err != nil {
+		t.Fatal(err)
+	}
+	return header
+}
+
+func TestApnsNotify(t *testing.T) {
+	key, authKey := newAuthKey(t)
+
+	var tokens []string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 {
+			t.Errorf("expected HTTP/2, got %s", r.Proto)
+		}
+		auth := r.Header.Get("authorization")
+		tokens = append(tokens, auth)
+		header := verifyToken(t, &key.PublicKey, strings.TrimPrefix(auth, "bearer "))
+		if header["alg"] != "ES256" || header["kid"] != "KEY123" {
+			t.Errorf("unexpected token header %v", header)
+		}
+		if r.Header.Get("apns-topic") != "com.example.app" ||
+			r.Header.Get("apns-push-type") != "alert" ||
+			r.Header.Get("apns-priority") != "10" ||
+			r.Header.Get("apns-collapse-id") != "c-1" ||
+			r.Header.Get("apns-expiration") != "1700000000" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		if r.URL.Path == devicePath+"bad" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"reason":"BadDeviceToken"}`))
+			return
+		}
+		var payload map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatal(err)
+		}
+		if payload["orderId"] != "42" || payload["aps"] == nil {
+			t.Errorf("unexpected payload %v", payload)
+		}
+		w.Header().Set("apns-id", "apns-1")
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	apClient, err := NewApnsClient(sdk.APNs{
+		Host:     server.URL,
+		BundleId: "com.example.app",
+		TeamId:   "TEAM123",
+		KeyId:    "KEY123",
+		AuthKey:  authKey,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trustServer(t, apClient, server)
+
+	req := &MessageRequest{
+		DeviceToken: "device",
+		Priority:    10,
+		PushType:    "alert",
+		CollapseId:  "c-1",
+		Expiration:  time.Unix(1700000000, 0),
+		Aps:         Aps{Alert: &Alert{Title: "Hello", Body: "Hello World"}},
+		Custom:      map[string]interface{}{"orderId": "42"},
+	}
+	notify, err := apClient.Notify(context.Background(), req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notify.GetResult() != "200" || notify.GetData()["apns-id"] != "apns-1" {
+		t.Errorf("unexpected response %v", notify)
+	}
+
+	req.DeviceToken = "bad"
+	notify, err = apClient.Notify(context.Background(), req)
+	if err == nil || !strings.Contains(err.Error(), "BadDeviceToken") {
+		t.Errorf("expected BadDeviceToken error, got %v", err)
+	}
+	if r, ok := notify.(*MessageResponse); !ok || r.Status != http.StatusBadRequest || r.Reason != "BadDeviceToken" {
+		t.Errorf("expected typed response with the failure, got %v", notify)
+	}
+
+	if len(tokens) != 2 || tokens[0] != tokens[1] {
+		t.Error("provider token should be reused within the refresh window")
+	}
+}
+
+func TestTokenRefreshWindow(t *testing.T) {
+	token := &TokenInfo{RefreshInterval: time.Minute}
+	token.Token = "t"
+	token.CreateTime = time.Now().Add(-10 * time.Minute)
+	if !token.IsValidate() {
+		t.Error("token should not be refreshed more often than every 20 minutes")
+	}
+	token.CreateTime = time.Now().Add(-55 * time.Minute)
+	token.RefreshInterval = 2 * time.Hour
+	if token.IsValidate() {
+		t.Error("token must be refreshed before it is 60 minutes old")
+	}
+}
+
+func TestFromMessage(t *testing.T) {
+	req, err := FromMessage(&sdk.Message{
+		Title:    "Hello",
+		SubTitle: "Sub",
+		Content:  "Hello World",
+		Extra:    map[string]string{"orderId": "42"},
+	}, "device-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	alert := req.Aps.Alert
+	if req.DeviceToken != "device-token" || req.PushType != "alert" || alert.Title != "Hello" ||
+		alert.Subtitle != "Sub" || alert.Body != "Hello World" {
+		t.Errorf("unexpected request %+v alert %+v", req, alert)
+	}
+	if req.Custom["orderId"] != "42" {
+		t.Errorf("unexpected custom %v", req.Custom)
+	}
+}
+
+// trustServer 让 NewApnsClient 创建的 HTTP/2 客户端信任测试服务器的证书，其余传输配置保持不变
+func trustServer(t *testing.T, c *client, server *httptest.Server) {
+	tr, ok := c.client.Client.Transport.(*http.Transport)
+	if !ok {
+		t.Fatalf("unexpected transport %T", c.client.Client.Transport)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	tr.TLSClientConfig.RootCAs = pool
+}
+
+func TestApnsUnregistered(t *testing.T) {
+	_, authKey := newAuthKey(t)
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 2 {
+			t.Errorf("expected HTTP/2, got %s", r.Proto)
+		}
+		w.Header().Set("apns-id", "apns-2")
+		w.WriteHeader(http.StatusGone)
+		w.Write([]byte(`{"reason":"Unregistered","timestamp":1700000000000}`))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+
+	apClient, err := NewApnsClient(sdk.APNs{
+		Host:     server.URL,
+		BundleId: "com.example.app",
+		TeamId:   "TEAM123",
+		KeyId:    "KEY123",
+		AuthKey:  authKey,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	trustServer(t, apClient, server)
+
+	notify, err := apClient.Notify(context.Background(), &MessageRequest{
+		DeviceToken: "device",
+		Aps:         Aps{Alert: &Alert{Title: "Hello"}},
+	})
+	if err == nil {
+		t.Fatal("expected error for unregistered device")
+	}
+	r, ok := notify.(*MessageResponse)
+	if !ok {
+		t.Fatalf("expected *MessageResponse, got %v", notify)
+	}
+	if r.Status != http.StatusGone || r.Reason != "Unregistered" || r.Timestamp != 1700000000000 || r.ApnsId != "apns-2" {
+		t.Errorf("unexpected response %+v", r)
+	}
+}
@@ -0,0 +1,44 @@
+package apns
+
+import (
+	sdk "github.com/holicc/push-sdk"
+)
+
+func init() {
+	sdk.Register(sdk.VendorAPNs, driver{})
+}
+
+type driver struct{}
+
+func (driver) Configured(cfg *sdk.PushConfig) bool {
+	return cfg.APNs.TeamId != "" ||
+		cfg.APNs.KeyId != "" ||
+		cfg.APNs.AuthKey != ""
+}
+
+func (driver) SetDefaults(cfg *sdk.PushConfig) {
+	SetDefaults(&cfg.APNs)
+}
+
+func (driver) NewClient(cfg *sdk.PushConfig) (sdk.PushClient, error) {
+	c, err := NewApnsClient(cfg.APNs)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (driver) Convert(msg *sdk.Message, token string) (sdk.MessageRequest, error) {
+	req, err := FromMessage(msg, token)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (driver) Check(resp sdk.MessageResponse) error {
+	if r, ok := resp.(*MessageResponse); ok {
+		return r.err()
+	}
+	return nil
+}
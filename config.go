@@ -0,0 +1,82 @@
+package push_sdk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ConfigError 汇总配置中的全部问题，而不是在第一个问题处返回
+type ConfigError struct {
+	Problems []string
+}
+
+func (e *ConfigError) Error() string {
+	return "invalid push config: " + strings.Join(e.Problems, "; ")
+}
+
+func (e *ConfigError) add(format string, args ...interface{}) {
+	e.Problems = append(e.Problems, fmt.Sprintf(format, args...))
+}
+
+// merge 追加另一步骤返回的错误，*ConfigError 展开为各自的问题
+func (e *ConfigError) merge(err error) {
+	if err == nil {
+		return
+	}
+	if other, ok := err.(*ConfigError); ok {
+		e.Problems = append(e.Problems, other.Problems...)
+		return
+	}
+	e.Problems = append(e.Problems, err.Error())
+}
+
+func (e *ConfigError) orNil() error {
+	if len(e.Problems) == 0 {
+		return nil
+	}
+	return e
+}
+
+// SetDefaults 为已注册厂商中未填写的接口地址填充官方默认地址
+func (c *PushConfig) SetDefaults() {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	for _, driver := range drivers {
+		driver.SetDefaults(c)
+	}
+}
+
+// NewClients 填充默认地址后为配置中填写的每个厂商创建客户端，
+// 任一厂商凭证缺失时返回包含全部问题的 *ConfigError
+func NewClients(cfg PushConfig) (map[string]PushClient, error) {
+	cfg.SetDefaults()
+
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+
+	vendors := make([]string, 0, len(drivers))
+	for vendor := range drivers {
+		vendors = append(vendors, vendor)
+	}
+	sort.Strings(vendors)
+
+	var cfgErr ConfigError
+	clients := make(map[string]PushClient)
+	for _, vendor := range vendors {
+		driver := drivers[vendor]
+		if !driver.Configured(&cfg) {
+			continue
+		}
+		client, err := driver.NewClient(&cfg)
+		if err != nil {
+			cfgErr.add("%s: %v", vendor, err)
+			continue
+		}
+		clients[vendor] = client
+	}
+	if err := cfgErr.orNil(); err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
@@ -0,0 +1,58 @@
+package push_sdk_test
+
+import (
+	sdk "github.com/holicc/push-sdk"
+	_ "github.com/holicc/push-sdk/meizu"
+	"github.com/holicc/push-sdk/oppo"
+	"github.com/holicc/push-sdk/vivo"
+	"github.com/holicc/push-sdk/xiaomi"
+	"testing"
+)
+
+func TestSetDefaults(t *testing.T) {
+	cfg := sdk.PushConfig{
+		Vivo: sdk.Vivo{Platform: sdk.Platform{PushURL: "https://example.com/send"}},
+	}
+	cfg.SetDefaults()
+
+	if cfg.XiaoMi.PushURL != xiaomi.DefaultPushURL || cfg.XiaoMi.FeedbackHost != xiaomi.DefaultFeedbackHost {
+		t.Errorf("unexpected xiaomi platform %v", cfg.XiaoMi.Platform)
+	}
+	if cfg.Oppo.AuthURL != oppo.DefaultAuthURL || cfg.Oppo.BroadcastURL != oppo.DefaultBroadcastURL {
+		t.Errorf("unexpected oppo platform %v", cfg.Oppo.Platform)
+	}
+	if cfg.Vivo.PushURL != "https://example.com/send" || cfg.Vivo.AuthURL != vivo.DefaultAuthURL {
+		t.Errorf("configured url should be kept, got %v", cfg.Vivo.Platform)
+	}
+}
+
+func TestNewClients(t *testing.T) {
+	clients, err := sdk.NewClients(sdk.PushConfig{
+		XiaoMi: sdk.XiaoMi{AppPkgName: "com.example", AppSecret: "secret"},
+		MeiZu:  sdk.MeiZu{AppId: "100", AppSecret: "secret"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := clients[sdk.VendorXiaoMi]; !ok {
+		t.Error("xiaomi client missing")
+	}
+	if _, ok := clients[sdk.VendorMeiZu]; !ok {
+		t.Error("meizu client missing")
+	}
+	if _, ok := clients[sdk.VendorOppo]; ok {
+		t.Error("oppo is not configured")
+	}
+
+	_, err = sdk.NewClients(sdk.PushConfig{
+		Oppo: sdk.Oppo{AppKey: "key"},
+		Vivo: sdk.Vivo{AppId: "1"},
+	})
+	cfgErr, ok := err.(*sdk.ConfigError)
+	if !ok {
+		t.Fatalf("expected *ConfigError, got %v", err)
+	}
+	if len(cfgErr.Problems) != 2 {
+		t.Errorf("expected problems for oppo and vivo, got %v", cfgErr.Problems)
+	}
+}
@@ -0,0 +1,151 @@
+package push_sdk
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+const (
+	VendorXiaoMi  = "xiaomi"
+	VendorMeiZu   = "meizu"
+	VendorOppo    = "oppo"
+	VendorVivo    = "vivo"
+	VendorHuawei  = "huawei"
+	VendorHonor   = "honor"
+	VendorAPNs    = "apns"
+	VendorFCM     = "fcm"
+	VendorWebPush = "webpush"
+)
+
+var ErrVendorNotConfigured = errors.New("vendor not configured")
+
+// Driver 由各厂商包在 init 中通过 Register 注册，根包借此构建客户端而无需引用厂商包
+type Driver interface {
+	// Configured 判断配置中是否填写了该厂商
+	Configured(cfg *PushConfig) bool
+	// SetDefaults 为未填写的接口地址填充官方默认地址
+	SetDefaults(cfg *PushConfig)
+	NewClient(cfg *PushConfig) (PushClient, error)
+	// Convert 将通用消息转换为发送到 token 的厂商消息
+	Convert(msg *Message, token string) (MessageRequest, error)
+	// Check 判断 Notify 成功返回的响应是否表示厂商拒绝了发往该设备的推送，
+	// 部分厂商在接口调用成功但推送失败时不返回错误
+	Check(resp MessageResponse) error
+}
+
+var (
+	driversMu sync.RWMutex
+	drivers   = make(map[string]Driver)
+)
+
+// Register 注册厂商驱动，重复注册同一厂商会 panic
+func Register(vendor string, d Driver) {
+	driversMu.Lock()
+	defer driversMu.Unlock()
+	if d == nil {
+		panic("push: register driver is nil")
+	}
+	if _, dup := drivers[vendor]; dup {
+		panic("push: register called twice for vendor " + vendor)
+	}
+	drivers[vendor] = d
+}
+
+// Vendors 返回已注册的厂商，按名称排序
+func Vendors() []string {
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+	list := make([]string, 0, len(drivers))
+	for vendor := range drivers {
+		list = append(list, vendor)
+	}
+	sort.Strings(list)
+	return list
+}
+
+type Device struct {
+	Vendor string `json:"vendor"`
+	Token  string `json:"token"`
+}
+
+type Result struct {
+	Device   Device
+	Response MessageResponse
+	Err      error
+}
+
+type Dispatcher struct {
+	clients map[string]PushClient
+	drivers map[string]Driver
+}
+
+// NewDispatcher 通过 NewClients 为配置中填写的每个厂商创建一个客户端，厂商包需先被导入以完成注册
+func NewDispatcher(cfg PushConfig) (*Dispatcher, error) {
+	clients, err := NewClients(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	driversMu.RLock()
+	defer driversMu.RUnlock()
+
+	d := &Dispatcher{
+		clients: clients,
+		drivers: make(map[string]Driver, len(clients)),
+	}
+	for vendor := range clients {
+		d.drivers[vendor] = drivers[vendor]
+	}
+	return d, nil
+}
+
+// Client 返回厂商对应的客户端
+func (d *Dispatcher) Client(vendor string) (PushClient, bool) {
+	c, ok := d.clients[vendor]
+	return c, ok
+}
+
+// Send 按厂商分组并发下发，组内逐个设备发送，结果顺序与 devices 一致；
+// 厂商拒绝推送时 Result 同时包含响应与错误
+func (d *Dispatcher) Send(ctx context.Context, msg Message, devices []Device) []Result {
+	results := make([]Result, len(devices))
+	groups := make(map[string][]int)
+	for i, device := range devices {
+		results[i].Device = device
+		if _, ok := d.clients[device.Vendor]; !ok {
+			results[i].Err = ErrVendorNotConfigured
+			continue
+		}
+		groups[device.Vendor] = append(groups[device.Vendor], i)
+	}
+
+	var wg sync.WaitGroup
+	for vendor, indexes := range groups {
+		wg.Add(1)
+		go func(vendor string, indexes []int) {
+			defer wg.Done()
+			client, driver := d.clients[vendor], d.drivers[vendor]
+			for _, i := range indexes {
+				if err := ctx.Err(); err != nil {
+					results[i].Err = err
+					continue
+				}
+				req, err := driver.Convert(&msg, devices[i].Token)
+				if err != nil {
+					results[i].Err = err
+					continue
+				}
+				resp, err := client.Notify(ctx, req)
+				if err == nil && resp != nil {
+					err = driver.Check(resp)
+				}
+				results[i].Response, results[i].Err = resp, err
+			}
+		}(vendor, indexes)
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,107 @@
+package push_sdk
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type fakeRequest struct {
+	token string
+}
+
+func (r *fakeRequest) Validate() error                 { return nil }
+func (r *fakeRequest) GetRequestBody() ([]byte, error) { return []byte(r.token), nil }
+
+type fakeResponse struct {
+	token  string
+	result string
+}
+
+func (r *fakeResponse) GetResult() string          { return r.result }
+func (r *fakeResponse) GetData() map[string]string { return map[string]string{"token": r.token} }
+
+type fakeClient struct {
+	mu   sync.Mutex
+	sent []string
+}
+
+func (c *fakeClient) Configured(cfg *PushConfig) bool               { return cfg.MeiZu.AppPkgName == "fake" }
+func (c *fakeClient) NewClient(cfg *PushConfig) (PushClient, error) { return c, nil }
+func (c *fakeClient) SetDefaults(cfg *PushConfig) {
+	if cfg.MeiZu.Host == "" {
+		cfg.MeiZu.Host = "https://fake.example.com"
+	}
+}
+
+func (c *fakeClient) Convert(msg *Message, token string) (MessageRequest, error) {
+	if token == "" {
+		return nil, errors.New("token empty")
+	}
+	return &fakeRequest{token: msg.Title + ":" + token}, nil
+}
+
+func (c *fakeClient) Notify(ctx context.Context, req MessageRequest) (MessageResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	token := req.(*fakeRequest).token
+	c.sent = append(c.sent, token)
+	// 与小米、OPPO、vivo 相同，厂商拒绝推送时仍返回 nil 错误
+	if strings.HasSuffix(token, ":rejected") {
+		return &fakeResponse{token: token, result: "rejected"}, nil
+	}
+	return &fakeResponse{token: token, result: "ok"}, nil
+}
+
+func (c *fakeClient) Check(resp MessageResponse) error {
+	if resp.GetResult() != "ok" {
+		return errors.New("push rejected")
+	}
+	return nil
+}
+
+func TestDispatcherSend(t *testing.T) {
+	fake := &fakeClient{}
+	Register("fake", fake)
+	defer func() {
+		driversMu.Lock()
+		delete(drivers, "fake")
+		driversMu.Unlock()
+	}()
+
+	d, err := NewDispatcher(PushConfig{MeiZu: MeiZu{AppPkgName: "fake"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := d.Send(context.Background(), Message{Title: "hi"}, []Device{
+		{Vendor: "fake", Token: "a"},
+		{Vendor: "unknown", Token: "b"},
+		{Vendor: "fake", Token: ""},
+		{Vendor: "fake", Token: "c"},
+		{Vendor: "fake", Token: "rejected"},
+	})
+
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(results))
+	}
+	if results[0].Err != nil || results[0].Response.GetData()["token"] != "hi:a" {
+		t.Errorf("unexpected result %v", results[0])
+	}
+	if results[1].Err != ErrVendorNotConfigured {
+		t.Errorf("expected vendor not configured, got %v", results[1].Err)
+	}
+	if results[2].Err == nil {
+		t.Error("expected convert error for empty token")
+	}
+	if results[3].Err != nil || results[3].Device.Token != "c" {
+		t.Errorf("unexpected result %v", results[3])
+	}
+	if results[4].Err == nil || results[4].Response == nil || results[4].Response.GetResult() != "rejected" {
+		t.Errorf("expected rejected push to carry response and error, got %v", results[4])
+	}
+	if len(fake.sent) != 3 {
+		t.Errorf("expected 3 notifications, got %v", fake.sent)
+	}
+}
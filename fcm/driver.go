@@ -0,0 +1,40 @@
+package fcm
+
+import (
+	sdk "github.com/holicc/push-sdk"
+)
+
+func init() {
+	sdk.Register(sdk.VendorFCM, driver{})
+}
+
+type driver struct{}
+
+func (driver) Configured(cfg *sdk.PushConfig) bool {
+	return cfg.FCM.ServiceAccount != ""
+}
+
+func (driver) SetDefaults(cfg *sdk.PushConfig) {
+	SetDefaults(&cfg.FCM)
+}
+
+func (driver) NewClient(cfg *sdk.PushConfig) (sdk.PushClient, error) {
+	c, err := NewFCMClient(cfg.FCM)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (driver) Convert(msg *sdk.Message, token string) (sdk.MessageRequest, error) {
+	req, err := FromMessage(msg, token)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// Check 不做额外判断，FCM 拒绝推送时返回非200状态码，Notify 已返回错误
+func (driver) Check(resp sdk.MessageResponse) error {
+	return nil
+}
@@ -0,0 +1,372 @@
+package fcm
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	sdk "github.com/holicc/push-sdk"
+	"github.com/holicc/push-sdk/http"
+	"github.com/holicc/push-sdk/jwt"
+	"net/url"
+	"time"
+)
+
+const (
+	DefaultPushURL = "https://fcm.googleapis.com/v1/projects/%s/messages:send"
+	DefaultAuthURL = "https://oauth2.googleapis.com/token"
+
+	messagingScope = "https://www.googleapis.com/auth/firebase.messaging"
+	jwtBearerGrant = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+
+	// 提前刷新 token，避免请求途中过期
+	tokenRefreshAhead = 5 * time.Minute
+)
+
+type MessageRequest struct {
+	ValidateOnly bool    `json:"validate_only,omitempty"` // 仅校验消息，不实际下发
+	Message      Message `json:"message"`
+}
+
+type Message struct {
+	Token        string            `json:"token,omitempty"`     // 按注册 token 推送
+	Topic        string            `json:"topic,omitempty"`     // 按主题推送
+	Condition    string            `json:"condition,omitempty"` // 按条件推送，如 "'a' in topics && 'b' in topics"
+	Data         map[string]string `json:"data,omitempty"`
+	Notification *Notification     `json:"notification,omitempty"`
+	Android      *AndroidConfig    `json:"android,omitempty"`
+	Webpush      *WebpushConfig    `json:"webpush,omitempty"`
+	Apns         *ApnsConfig       `json:"apns,omitempty"`
+	FcmOptions   *FcmOptions       `json:"fcm_options,omitempty"`
+}
+
+type Notification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+	Image string `json:"image,omitempty"`
+}
+
+type AndroidConfig struct {
+	CollapseKey           string               `json:"collapse_key,omitempty"`
+	Priority              string               `json:"priority,omitempty"` // NORMAL 或 HIGH
+	TTL                   string               `json:"ttl,omitempty"`      // 如 "3600s"
+	RestrictedPackageName string               `json:"restricted_package_name,omitempty"`
+	Data                  map[string]string    `json:"data,omitempty"`
+	Notification          *AndroidNotification `json:"notification,omitempty"`
+	FcmOptions            *FcmOptions          `json:"fcm_options,omitempty"`
+	DirectBootOk          bool                 `json:"direct_boot_ok,omitempty"`
+}
+
+type AndroidNotification struct {
+	Title       string `json:"title,omitempty"`
+	Body        string `json:"body,omitempty"`
+	Icon        string `json:"icon,omitempty"`
+	Color       string `json:"color,omitempty"`
+	Sound       string `json:"sound,omitempty"`
+	Tag         string `json:"tag,omitempty"`
+	ClickAction string `json:"click_action,omitempty"`
+	ChannelId   string `json:"channel_id,omitempty"`
+	Image       string `json:"image,omitempty"`
+}
+
+type WebpushConfig struct {
+	Headers      map[string]string      `json:"headers,omitempty"`
+	Data         map[string]string      `json:"data,omitempty"`
+	Notification map[string]interface{} `json:"notification,omitempty"` // Web Notification API 的选项
+	FcmOptions   *WebpushFcmOptions     `json:"fcm_options,omitempty"`
+}
+
+type WebpushFcmOptions struct {
+	Link           string `json:"link,omitempty"` // 点击通知打开的链接，必须为 HTTPS
+	AnalyticsLabel string `json:"analytics_label,omitempty"`
+}
+
+type ApnsConfig struct {
+	Headers    map[string]string      `json:"headers,omitempty"` // 如 apns-priority、apns-push-type
+	Payload    map[string]interface{} `json:"payload,omitempty"` // 包含 aps 的完整 APNs payload
+	FcmOptions *ApnsFcmOptions        `json:"fcm_options,omitempty"`
+}
+
+type ApnsFcmOptions struct {
+	AnalyticsLabel string `json:"analytics_label,omitempty"`
+	Image          string `json:"image,omitempty"`
+}
+
+type FcmOptions struct {
+	AnalyticsLabel string `json:"analytics_label,omitempty"`
+}
+
+type MessageResponse struct {
+	Name string `json:"name"` // 消息 ID，格式为 projects/*/messages/{message_id}
+}
+
+type ErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Status  string `json:"status"` // 如 UNREGISTERED、INVALID_ARGUMENT
+	} `json:"error"`
+}
+
+// ServiceAccount 为 Google 服务账号密钥文件中用到的字段
+type ServiceAccount struct {
+	Type         string `json:"type"`
+	ProjectId    string `json:"project_id"`
+	PrivateKeyId string `json:"private_key_id"`
+	PrivateKey   string `json:"private_key"`
+	ClientEmail  string `json:"client_email"`
+	TokenURI     string `json:"token_uri"`
+}
+
+type TokenInfo struct {
+	ClientEmail  string
+	PrivateKeyId string
+	Key          *rsa.PrivateKey
+	AuthURL      string
+
+	Token      string
+	ExpireTime time.Time
+}
+
+type TokenResp struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"` // 有效时长，单位秒
+	TokenType        string `json:"token_type"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type client struct {
+	fc         sdk.FCM
+	client     *http.HTTPClient
+	authClient *http.AuthClient
+}
+
+func NewFCMClient(fc sdk.FCM) (*client, error) {
+	if fc.ServiceAccount == "" {
+		return nil, errors.New("service account empty")
+	}
+	var sa ServiceAccount
+	if err := json.Unmarshal([]byte(fc.ServiceAccount), &sa); err != nil {
+		return nil, err
+	}
+	if sa.ClientEmail == "" {
+		return nil, errors.New("service account client email empty")
+	}
+	key, err := jwt.ParseRSAPrivateKey([]byte(sa.PrivateKey))
+	if err != nil {
+		return nil, err
+	}
+	if fc.ProjectId == "" {
+		fc.ProjectId = sa.ProjectId
+	}
+	if fc.ProjectId == "" {
+		return nil, errors.New("project id empty")
+	}
+	if fc.AuthURL == "" {
+		fc.AuthURL = sa.TokenURI
+	}
+	SetDefaults(&fc)
+
+	hc := http.NewSecureHTTPClient()
+	return &client{
+		fc:     fc,
+		client: hc,
+		authClient: http.NewAuthClientWith(&TokenInfo{
+			ClientEmail:  sa.ClientEmail,
+			PrivateKeyId: sa.PrivateKeyId,
+			Key:          key,
+			AuthURL:      fc.AuthURL,
+		}, hc),
+	}, nil
+}
+
+// SetDefaults 为未填写的接口地址填充官方默认地址，推送地址依赖 ProjectId
+func SetDefaults(fc *sdk.FCM) {
+	if fc.PushURL == "" && fc.ProjectId != "" {
+		fc.PushURL = fmt.Sprintf(DefaultPushURL, fc.ProjectId)
+	}
+	if fc.AuthURL == "" {
+		fc.AuthURL = DefaultAuthURL
+	}
+}
+
+func (c *client) Notify(ctx context.Context, req sdk.MessageRequest) (sdk.MessageResponse, error) {
+	if e := req.Validate(); e != nil {
+		return nil, e
+	}
+
+	token, err := c.authClient.GetAuthToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return nil, errors.New("can not get token")
+	}
+
+	data, err := req.GetRequestBody()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(ctx, &http.PushRequest{
+		Method: "POST",
+		URL:    c.fc.PushURL,
+		Body:   data,
+		Header: []http.HTTPOption{
+			http.SetHeader("Content-Type", "application/json"),
+			http.SetHeader("Authorization", "Bearer "+token),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status != 200 {
+		var e ErrorResponse
+		if json.Unmarshal(resp.Body, &e) == nil && e.Error.Status != "" {
+			return nil, errors.New(fmt.Sprintf("notify request failed %s %s", e.Error.Status, e.Error.Message))
+		}
+		return nil, errors.New(fmt.Sprintf("notify request failed %s", string(resp.Body)))
+	}
+
+	var r MessageResponse
+	err = json.Unmarshal(resp.Body, &r)
+	if err != nil {
+		return nil, err
+	}
+
+	return &r, nil
+}
+
+func (m *MessageRequest) Validate() error {
+	msg := m.Message
+	targets := 0
+	if msg.Token != "" {
+		targets++
+	}
+	if msg.Topic != "" {
+		targets++
+	}
+	if msg.Condition != "" {
+		targets++
+	}
+	if targets != 1 {
+		return errors.New("exactly one of token, topic and condition must be set")
+	}
+	if msg.Android != nil && msg.Android.Priority != "" && msg.Android.Priority != "NORMAL" && msg.Android.Priority != "HIGH" {
+		return errors.New("wrong android priority")
+	}
+	if msg.Webpush != nil && msg.Webpush.FcmOptions != nil && msg.Webpush.FcmOptions.Link != "" {
+		u, err := url.Parse(msg.Webpush.FcmOptions.Link)
+		if err != nil || u.Scheme != "https" {
+			return errors.New("webpush link must be a https url")
+		}
+	}
+	return nil
+}
+
+func (m *MessageRequest) GetRequestBody() ([]byte, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (m *MessageResponse) GetResult() string {
+	return m.Name
+}
+
+func (m *MessageResponse) GetData() map[string]string {
+	return map[string]string{
+		"name": m.Name,
+	}
+}
+
+// TokenRequest 签发服务账号 JWT 断言，用于换取 OAuth2 access token
+func (t *TokenInfo) TokenRequest() ([]byte, error) {
+	now := time.Now()
+	assertion, err := jwt.SignRS256(t.Key, map[string]interface{}{
+		"typ": "JWT",
+		"kid": t.PrivateKeyId,
+	}, map[string]interface{}{
+		"iss":   t.ClientEmail,
+		"scope": messagingScope,
+		"aud":   t.AuthURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data := url.Values{}
+	data.Add("grant_type", jwtBearerGrant)
+	data.Add("assertion", assertion)
+	return []byte(data.Encode()), nil
+}
+
+func (t *TokenInfo) ParseResponse(d []byte) error {
+	var token TokenResp
+	err := json.Unmarshal(d, &token)
+	if err != nil {
+		return err
+	}
+	if token.AccessToken == "" {
+		return errors.New(fmt.Sprintf("get access token failed %s %s", token.Error, token.ErrorDescription))
+	}
+
+	t.Token = token.AccessToken
+	t.ExpireTime = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return nil
+}
+
+func (t *TokenInfo) SetHeader() []http.HTTPOption {
+	return []http.HTTPOption{
+		http.SetHeader("Content-Type", "application/x-www-form-urlencoded"),
+	}
+}
+
+func (t *TokenInfo) GetAuthUrl() string {
+	return t.AuthURL
+}
+
+func (t *TokenInfo) GetAuthMethod() string {
+	return "POST"
+}
+
+func (t *TokenInfo) GetAccessToken() string {
+	return t.Token
+}
+
+func (t *TokenInfo) IsValidate() bool {
+	return t.Token != "" && time.Now().Add(tokenRefreshAhead).Before(t.ExpireTime)
+}
+
+// FromMessage 将通用消息转换为发送到指定注册 token 的 FCM 消息，副标题仅在 APNs 覆盖块中生效
+func FromMessage(msg *sdk.Message, token string) (*MessageRequest, error) {
+	req := &MessageRequest{
+		Message: Message{
+			Token: token,
+			Data:  msg.Extra,
+			Notification: &Notification{
+				Title: msg.Title,
+				Body:  msg.Content,
+			},
+		},
+	}
+	if msg.SubTitle != "" {
+		req.Message.Apns = &ApnsConfig{
+			Payload: map[string]interface{}{
+				"aps": map[string]interface{}{
+					"alert": map[string]string{
+						"title":    msg.Title,
+						"subtitle": msg.SubTitle,
+						"body":     msg.Content,
+					},
+				},
+			},
+		}
+	}
+	return req, nil
+}
@@ -0,0 +1,136 @@
+package fcm
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	sdk "github.com/holicc/push-sdk"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestFCMNotify(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.PostForm.Get("grant_type") != jwtBearerGrant {
+			t.Errorf("unexpected grant type %s", r.PostForm.Get("grant_type"))
+		}
+		parts := strings.Split(r.PostForm.Get("assertion"), ".")
+		if len(parts) != 3 {
+			t.Fatalf("malformed assertion %v", parts)
+		}
+		sig, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+		if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], sig); err != nil {
+			t.Errorf("assertion signature verify failed %v", err)
+		}
+		claimsJson, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		var claims map[string]interface{}
+		if err := json.Unmarshal(claimsJson, &claims); err != nil {
+			t.Fatal(err)
+		}
+		if claims["iss"] != "push@demo.iam.gserviceaccount.com" || claims["aud"] != server.URL+"/token" || claims["scope"] != messagingScope {
+			t.Errorf("unexpected claims %v", claims)
+		}
+		w.Write([]byte(`{"access_token":"token-1","expires_in":3599,"token_type":"Bearer"}`))
+	})
+	mux.HandleFunc("/v1/projects/demo/messages:send", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			t.Errorf("unexpected authorization %s", r.Header.Get("Authorization"))
+		}
+		var req MessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Message.Topic != "news" || req.Message.Android.Priority != "HIGH" {
+			t.Errorf("unexpected message %v", req.Message)
+		}
+		w.Write([]byte(`{"name":"projects/demo/messages/1"}`))
+	})
+
+	sa, _ := json.Marshal(&ServiceAccount{
+		Type:         "service_account",
+		ProjectId:    "demo",
+		PrivateKeyId: "kid",
+		PrivateKey:   string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		ClientEmail:  "push@demo.iam.gserviceaccount.com",
+		TokenURI:     server.URL + "/token",
+	})
+	fcmClient, err := NewFCMClient(sdk.FCM{
+		Platform:       sdk.Platform{PushURL: server.URL + "/v1/projects/demo/messages:send"},
+		ServiceAccount: string(sa),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notify, err := fcmClient.Notify(context.Background(), &MessageRequest{
+		Message: Message{
+			Topic:        "news",
+			Notification: &Notification{Title: "Hello", Body: "Hello World"},
+			Android:      &AndroidConfig{Priority: "HIGH"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notify.GetResult() != "projects/demo/messages/1" {
+		t.Errorf("unexpected result %s", notify.GetResult())
+	}
+}
+
+func TestValidateTarget(t *testing.T) {
+	req := &MessageRequest{Message: Message{Token: "a", Topic: "b"}}
+	if req.Validate() == nil {
+		t.Error("expected error for multiple targets")
+	}
+	req = &MessageRequest{Message: Message{}}
+	if req.Validate() == nil {
+		t.Error("expected error for missing target")
+	}
+}
+
+func TestFromMessage(t *testing.T) {
+	req, err := FromMessage(&sdk.Message{
+		Title:    "Hello",
+		SubTitle: "Sub",
+		Content:  "Hello World",
+		Extra:    map[string]string{"orderId": "42"},
+	}, "token-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	m := req.Message
+	if m.Token != "token-1" || m.Notification.Title != "Hello" || m.Notification.Body != "Hello World" || m.Data["orderId"] != "42" {
+		t.Errorf("unexpected message %+v", m)
+	}
+	alert := m.Apns.Payload["aps"].(map[string]interface{})["alert"].(map[string]string)
+	if alert["subtitle"] != "Sub" {
+		t.Errorf("unexpected apns alert %v", alert)
+	}
+}
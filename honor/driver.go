@@ -0,0 +1,44 @@
+package honor
+
+import (
+	sdk "github.com/holicc/push-sdk"
+)
+
+func init() {
+	sdk.Register(sdk.VendorHonor, driver{})
+}
+
+type driver struct{}
+
+func (driver) Configured(cfg *sdk.PushConfig) bool {
+	return cfg.Honor.AppId != "" ||
+		cfg.Honor.ClientId != "" ||
+		cfg.Honor.ClientSecret != ""
+}
+
+func (driver) SetDefaults(cfg *sdk.PushConfig) {
+	SetDefaults(&cfg.Honor)
+}
+
+func (driver) NewClient(cfg *sdk.PushConfig) (sdk.PushClient, error) {
+	c, err := NewHonorClient(cfg.Honor)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (driver) Convert(msg *sdk.Message, token string) (sdk.MessageRequest, error) {
+	req, err := FromMessage(msg, token)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (driver) Check(resp sdk.MessageResponse) error {
+	if r, ok := resp.(*MessageResponse); ok {
+		return r.err()
+	}
+	return nil
+}
@@ -0,0 +1,317 @@
+package honor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	sdk "github.com/holicc/push-sdk"
+	"github.com/holicc/push-sdk/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultPushURL = "https://push-api.cloud.honor.com/api/v1/%s/sendMessage"
+	DefaultAuthURL = "https://iam.developer.honor.com/auth/token"
+
+	// 提前刷新 token，避免请求途中过期
+	tokenRefreshAhead = 5 * time.Minute
+)
+
+type MessageRequest struct {
+	Data         string         `json:"data,omitempty"` // 透传消息内容
+	Notification *Notification  `json:"notification,omitempty"`
+	Android      *AndroidConfig `json:"android,omitempty"`
+	Token        []string       `json:"token"` // 推送目标 token，最多1000个
+}
+
+type Notification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+	Image string `json:"image,omitempty"`
+}
+
+type AndroidConfig struct {
+	TTL            string               `json:"ttl,omitempty"`   // 如 "86400s"
+	BiTag          string               `json:"biTag,omitempty"` // 回执中携带的批量任务标识
+	Data           string               `json:"data,omitempty"`
+	Notification   *AndroidNotification `json:"notification,omitempty"`
+	TargetUserType int                  `json:"targetUserType,omitempty"` // 0 正式消息, 1 测试消息
+}
+
+type AndroidNotification struct {
+	Title       string       `json:"title,omitempty"`
+	Body        string       `json:"body,omitempty"`
+	ClickAction *ClickAction `json:"clickAction,omitempty"`
+	Image       string       `json:"image,omitempty"`
+	Style       int          `json:"style,omitempty"` // 0 默认样式, 1 大文本样式
+	BigTitle    string       `json:"bigTitle,omitempty"`
+	BigBody     string       `json:"bigBody,omitempty"`
+	Importance  string       `json:"importance,omitempty"` // LOW 或 NORMAL
+	Tag         string       `json:"tag,omitempty"`
+	Group       string       `json:"group,omitempty"`
+	NotifyId    int          `json:"notifyId,omitempty"`
+}
+
+type ClickAction struct {
+	Type   int    `json:"type"`             // 1 打开应用自定义页面, 2 打开URL, 3 打开应用
+	Intent string `json:"intent,omitempty"` // type 为 1 时的 intent
+	Action string `json:"action,omitempty"` // type 为 1 时的 action
+	Url    string `json:"url,omitempty"`    // type 为 2 时的 URL
+}
+
+type MessageResponse struct {
+	Code    int          `json:"code"` // 200 表示成功
+	Message string       `json:"message"`
+	Data    *MessageData `json:"data"`
+}
+
+type MessageData struct {
+	SendResult   bool     `json:"sendResult"`
+	RequestId    string   `json:"requestId"`
+	FailTokens   []string `json:"failTokens"`   // 发送失败的 token
+	ExpireTokens []string `json:"expireTokens"` // 已过期的 token
+}
+
+type TokenInfo struct {
+	ClientId     string
+	ClientSecret string
+	AuthURL      string
+
+	Token      string
+	ExpireTime time.Time
+}
+
+type TokenResp struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"` // 有效时长，单位秒
+	TokenType        string `json:"token_type"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type client struct {
+	ho         sdk.Honor
+	client     *http.HTTPClient
+	authClient *http.AuthClient
+}
+
+func NewHonorClient(ho sdk.Honor) (*client, error) {
+	if ho.AppId == "" {
+		return nil, errors.New("app id empty")
+	}
+	if ho.ClientId == "" {
+		return nil, errors.New("client id empty")
+	}
+	if ho.ClientSecret == "" {
+		return nil, errors.New("client secret empty")
+	}
+	SetDefaults(&ho)
+
+	hc := http.NewSecureHTTPClient()
+	return &client{
+		ho:     ho,
+		client: hc,
+		authClient: http.NewAuthClientWith(&TokenInfo{
+			ClientId:     ho.ClientId,
+			ClientSecret: ho.ClientSecret,
+			AuthURL:      ho.AuthURL,
+		}, hc),
+	}, nil
+}
+
+// SetDefaults 为未填写的接口地址填充官方默认地址，推送地址依赖 AppId
+func SetDefaults(ho *sdk.Honor) {
+	if ho.PushURL == "" && ho.AppId != "" {
+		ho.PushURL = fmt.Sprintf(DefaultPushURL, ho.AppId)
+	}
+	if ho.AuthURL == "" {
+		ho.AuthURL = DefaultAuthURL
+	}
+}
+
+func (c *client) Notify(ctx context.Context, req sdk.MessageRequest) (sdk.MessageResponse, error) {
+	if e := req.Validate(); e != nil {
+		return nil, e
+	}
+
+	token, err := c.authClient.GetAuthToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return nil, errors.New("can not get token")
+	}
+
+	data, err := req.GetRequestBody()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(ctx, &http.PushRequest{
+		Method: "POST",
+		URL:    c.ho.PushURL,
+		Body:   data,
+		Header: []http.HTTPOption{
+			http.SetHeader("Content-Type", "application/json"),
+			http.SetHeader("Authorization", "Bearer "+token),
+			http.SetHeader("timestamp", strconv.FormatInt(time.Now().UnixNano()/1e6, 10)),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status != 200 {
+		return nil, errors.New(fmt.Sprintf("notify request failed %s", string(resp.Body)))
+	}
+
+	var r MessageResponse
+	err = json.Unmarshal(resp.Body, &r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Code != 200 {
+		return nil, errors.New(fmt.Sprintf("notify request failed code %d %s", r.Code, r.Message))
+	}
+
+	return &r, nil
+}
+
+func (m *MessageRequest) Validate() error {
+	if len(m.Token) == 0 {
+		return errors.New("token empty")
+	}
+	if len(m.Token) > 1000 {
+		return errors.New("too many tokens, at most 1000")
+	}
+	if m.Data == "" && m.Notification == nil && (m.Android == nil || m.Android.Notification == nil) {
+		return errors.New("message data and notification are both empty")
+	}
+	if m.Android != nil && m.Android.Notification != nil {
+		action := m.Android.Notification.ClickAction
+		if action == nil {
+			return errors.New("android notification click action is empty")
+		}
+		switch action.Type {
+		case 1:
+			if action.Intent == "" && action.Action == "" {
+				return errors.New("click action intent or action is empty")
+			}
+		case 2:
+			if action.Url == "" {
+				return errors.New("click action url is empty")
+			}
+		case 3:
+		default:
+			return errors.New("wrong click action type")
+		}
+	}
+	return nil
+}
+
+func (m *MessageRequest) GetRequestBody() ([]byte, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// err 判断荣耀是否拒绝了推送，code 为200时仍可能有 token 发送失败或已过期
+func (m *MessageResponse) err() error {
+	if m.Code != 200 {
+		return errors.New(fmt.Sprintf("notify request failed code %d %s", m.Code, m.Message))
+	}
+	if m.Data != nil && (len(m.Data.FailTokens) > 0 || len(m.Data.ExpireTokens) > 0) {
+		return errors.New(fmt.Sprintf("notify failed tokens %v expired tokens %v", m.Data.FailTokens, m.Data.ExpireTokens))
+	}
+	return nil
+}
+
+func (m *MessageResponse) GetResult() string {
+	return strconv.Itoa(m.Code)
+}
+
+func (m *MessageResponse) GetData() map[string]string {
+	if m.Data == nil {
+		return nil
+	}
+	return map[string]string{
+		"requestId": m.Data.RequestId,
+	}
+}
+
+func (t *TokenInfo) TokenRequest() ([]byte, error) {
+	data := url.Values{}
+	data.Add("grant_type", "client_credentials")
+	data.Add("client_id", t.ClientId)
+	data.Add("client_secret", t.ClientSecret)
+	return []byte(data.Encode()), nil
+}
+
+func (t *TokenInfo) ParseResponse(d []byte) error {
+	var token TokenResp
+	err := json.Unmarshal(d, &token)
+	if err != nil {
+		return err
+	}
+	if token.AccessToken == "" {
+		return errors.New(fmt.Sprintf("get access token failed %s %s", token.Error, token.ErrorDescription))
+	}
+
+	t.Token = token.AccessToken
+	t.ExpireTime = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return nil
+}
+
+func (t *TokenInfo) SetHeader() []http.HTTPOption {
+	return []http.HTTPOption{
+		http.SetHeader("Content-Type", "application/x-www-form-urlencoded"),
+	}
+}
+
+func (t *TokenInfo) GetAuthUrl() string {
+	return t.AuthURL
+}
+
+func (t *TokenInfo) GetAuthMethod() string {
+	return "POST"
+}
+
+func (t *TokenInfo) GetAccessToken() string {
+	return t.Token
+}
+
+func (t *TokenInfo) IsValidate() bool {
+	return t.Token != "" && time.Now().Add(tokenRefreshAhead).Before(t.ExpireTime)
+}
+
+// FromMessage 将通用消息转换为发送到指定 token 的荣耀通知栏消息，点击打开应用；
+// 自定义参数放入 data，回执参数作为 biTag 随回执返回。荣耀通知没有副标题，回执地址需在
+// 荣耀控制台配置，因此 SubTitle 与 CallBack 不被支持
+func FromMessage(msg *sdk.Message, token string) (*MessageRequest, error) {
+	req := &MessageRequest{
+		Token: []string{token},
+		Notification: &Notification{
+			Title: msg.Title,
+			Body:  msg.Content,
+		},
+		Android: &AndroidConfig{
+			BiTag: msg.CallbackParam,
+			Notification: &AndroidNotification{
+				Title:       msg.Title,
+				Body:        msg.Content,
+				ClickAction: &ClickAction{Type: 3},
+			},
+		},
+	}
+	if len(msg.Extra) > 0 {
+		data, err := json.Marshal(msg.Extra)
+		if err != nil {
+			return nil, err
+		}
+		req.Data = string(data)
+	}
+	return req, nil
+}
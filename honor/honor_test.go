@@ -0,0 +1,92 @@
+package honor
+
+import (
+	"context"
+	"encoding/json"
+	sdk "github.com/holicc/push-sdk"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHonorNotify(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.PostForm.Get("client_id") != "client" || r.PostForm.Get("client_secret") != "secret" {
+			t.Errorf("unexpected token request %v", r.PostForm)
+		}
+		w.Write([]byte(`{"access_token":"token-1","expires_in":3600,"token_type":"Bearer"}`))
+	})
+	mux.HandleFunc("/api/v1/104400/sendMessage", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			t.Errorf("unexpected authorization %s", r.Header.Get("Authorization"))
+		}
+		if r.Header.Get("timestamp") == "" {
+			t.Error("timestamp header empty")
+		}
+		var req MessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if req.Android.Notification.ClickAction.Type != 3 {
+			t.Errorf("unexpected click action %v", req.Android.Notification.ClickAction)
+		}
+		w.Write([]byte(`{"code":200,"message":"success","data":{"sendResult":true,"requestId":"r-1","failTokens":[],"expireTokens":[]}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	hoClient, err := NewHonorClient(sdk.Honor{
+		Platform: sdk.Platform{
+			PushURL: server.URL + "/api/v1/104400/sendMessage",
+			AuthURL: server.URL + "/auth/token",
+		},
+		AppId:        "104400",
+		ClientId:     "client",
+		ClientSecret: "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notify, err := hoClient.Notify(context.Background(), &MessageRequest{
+		Token: []string{"device-token"},
+		Android: &AndroidConfig{
+			Notification: &AndroidNotification{
+				Title:       "Hello",
+				Body:        "Hello World",
+				ClickAction: &ClickAction{Type: 3},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notify.GetResult() != "200" || notify.GetData()["requestId"] != "r-1" {
+		t.Errorf("unexpected response %v", notify)
+	}
+}
+
+func TestFromMessage(t *testing.T) {
+	req, err := FromMessage(&sdk.Message{
+		Title:         "Hello",
+		Content:       "Hello World",
+		Extra:         map[string]string{"orderId": "42"},
+		CallbackParam: "biz-1",
+	}, "token-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(req.Token) != 1 || req.Token[0] != "token-1" || req.Notification.Title != "Hello" || req.Notification.Body != "Hello World" {
+		t.Errorf("unexpected request %+v", req)
+	}
+	n := req.Android.Notification
+	if req.Data != `{"orderId":"42"}` || req.Android.BiTag != "biz-1" || n.Title != "Hello" || n.ClickAction.Type != 3 {
+		t.Errorf("unexpected android config %+v data %s", req.Android, req.Data)
+	}
+}
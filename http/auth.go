@@ -2,11 +2,14 @@ package http
 
 import (
 	"context"
+	"sync"
 )
 
+// AuthClient 缓存鉴权 token，可被多个 goroutine 共享
 type AuthClient struct {
 	client *HTTPClient
 	token  Token
+	mu     sync.Mutex // 保护 token 的检查与刷新
 }
 
 type Token interface {
@@ -22,18 +25,40 @@ type Token interface {
 	IsValidate() bool
 }
 
+// TokenGenerator 由本地签发 token 的实现提供（如 APNs 的 JWT），
+// AuthClient 在 token 失效时直接调用 GenerateToken 而不发起鉴权请求
+type TokenGenerator interface {
+	GenerateToken() error
+}
+
 func NewAuthClient(t Token) *AuthClient {
+	return NewAuthClientWith(t, NewHTTPClient())
+}
+
+// NewAuthClientWith 使用指定的 HTTP 客户端发起鉴权请求，如 NewSecureHTTPClient
+func NewAuthClientWith(t Token, client *HTTPClient) *AuthClient {
 	return &AuthClient{
 		token:  t,
-		client: NewHTTPClient(),
+		client: client,
 	}
 }
 
+// GetAuthToken 返回缓存的 token，失效时刷新；并发调用时只有一个 goroutine 发起刷新
 func (ac *AuthClient) GetAuthToken(ctx context.Context) (string, error) {
+	ac.mu.Lock()
+	defer ac.mu.Unlock()
+
 	if ac.token.IsValidate() {
 		return ac.token.GetAccessToken(), nil
 	}
 
+	if g, ok := ac.token.(TokenGenerator); ok {
+		if err := g.GenerateToken(); err != nil {
+			return "", err
+		}
+		return ac.token.GetAccessToken(), nil
+	}
+
 	body, err := ac.token.TokenRequest()
 	if err != nil {
 		return "", err
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type testToken struct {
+	url        string
+	token      string
+	createTime time.Time
+}
+
+func (t *testToken) TokenRequest() ([]byte, error) {
+	return nil, nil
+}
+
+func (t *testToken) ParseResponse(d []byte) error {
+	t.token = string(d)
+	t.createTime = time.Now()
+	return nil
+}
+
+func (t *testToken) SetHeader() []HTTPOption {
+	return nil
+}
+
+func (t *testToken) GetAuthUrl() string {
+	return t.url
+}
+
+func (t *testToken) GetAuthMethod() string {
+	return "POST"
+}
+
+func (t *testToken) GetAccessToken() string {
+	return t.token
+}
+
+func (t *testToken) IsValidate() bool {
+	return t.token != "" && time.Now().Sub(t.createTime) < time.Hour
+}
+
+func TestAuthClientConcurrent(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte("token"))
+	}))
+	defer server.Close()
+
+	ac := NewAuthClient(&testToken{url: server.URL})
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := ac.GetAuthToken(context.Background())
+			if err != nil || token != "token" {
+				t.Errorf("unexpected token %q %v", token, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 只有第一个调用发起鉴权请求，其余调用复用缓存的 token
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Errorf("expected 1 auth request, got %d", n)
+	}
+}
@@ -78,6 +78,39 @@ func NewHTTPClient() *HTTPClient {
 	}
 }
 
+// NewSecureHTTPClient 返回校验服务端证书的客户端，供传输凭证或访问外部地址的厂商使用
+func NewSecureHTTPClient() *HTTPClient {
+	tr := &http.Transport{
+		MaxIdleConns:       10,
+		IdleConnTimeout:    30 * time.Second,
+		DisableCompression: true,
+	}
+
+	return &HTTPClient{
+		Client: &http.Client{Transport: tr},
+		RetryConfig: &HTTPRetryConfig{
+			MaxRetryTimes: 4,
+			RetryInterval: 0},
+	}
+}
+
+// NewHTTP2Client 返回强制协商 HTTP/2 且校验证书的客户端，供 APNs 等仅支持 HTTP/2 的服务使用
+func NewHTTP2Client() *HTTPClient {
+	tr := &http.Transport{
+		MaxIdleConns:      10,
+		IdleConnTimeout:   60 * time.Second,
+		ForceAttemptHTTP2: true,
+		TLSClientConfig:   &tls.Config{},
+	}
+
+	return &HTTPClient{
+		Client: &http.Client{Transport: tr},
+		RetryConfig: &HTTPRetryConfig{
+			MaxRetryTimes: 4,
+			RetryInterval: 0},
+	}
+}
+
 func (r *PushRequest) buildHTTPRequest() (*http.Request, error) {
 	var body io.Reader
 
@@ -0,0 +1,33 @@
+package http
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestSecureHTTPClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	client := NewSecureHTTPClient()
+	if _, err := client.Do(context.Background(), &PushRequest{Method: "GET", URL: server.URL}); err == nil {
+		t.Fatal("expected certificate error for untrusted server")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(server.Certificate())
+	client.Client.Transport.(*http.Transport).TLSClientConfig = &tls.Config{RootCAs: pool}
+	resp, err := client.Do(context.Background(), &PushRequest{Method: "GET", URL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != 200 || string(resp.Body) != "ok" {
+		t.Errorf("unexpected response %d %s", resp.Status, resp.Body)
+	}
+}
@@ -0,0 +1,43 @@
+package huawei
+
+import (
+	sdk "github.com/holicc/push-sdk"
+)
+
+func init() {
+	sdk.Register(sdk.VendorHuawei, driver{})
+}
+
+type driver struct{}
+
+func (driver) Configured(cfg *sdk.PushConfig) bool {
+	return cfg.Huawei.AppId != "" ||
+		cfg.Huawei.AppSecret != ""
+}
+
+func (driver) SetDefaults(cfg *sdk.PushConfig) {
+	SetDefaults(&cfg.Huawei)
+}
+
+func (driver) NewClient(cfg *sdk.PushConfig) (sdk.PushClient, error) {
+	c, err := NewHuaweiClient(cfg.Huawei)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (driver) Convert(msg *sdk.Message, token string) (sdk.MessageRequest, error) {
+	req, err := FromMessage(msg, token)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (driver) Check(resp sdk.MessageResponse) error {
+	if r, ok := resp.(*MessageResponse); ok {
+		return r.err()
+	}
+	return nil
+}
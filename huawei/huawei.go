@@ -0,0 +1,332 @@
+package huawei
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	sdk "github.com/holicc/push-sdk"
+	"github.com/holicc/push-sdk/http"
+	"net/url"
+	"time"
+)
+
+const (
+	DefaultPushURL = "https://push-api.cloud.huawei.com/v1/%s/messages:send"
+	DefaultAuthURL = "https://oauth-login.cloud.huawei.com/oauth2/v3/token"
+
+	successCode        = "80000000"
+	partialSuccessCode = "80100000"
+
+	// 提前刷新 token，避免请求途中过期
+	tokenRefreshAhead = 5 * time.Minute
+)
+
+type MessageRequest struct {
+	ValidateOnly bool    `json:"validate_only"` // 仅校验消息，不实际下发
+	Message      Message `json:"message"`
+}
+
+type Message struct {
+	Data         string         `json:"data,omitempty"` // 透传消息内容
+	Notification *Notification  `json:"notification,omitempty"`
+	Android      *AndroidConfig `json:"android,omitempty"`
+	Token        []string       `json:"token,omitempty"`     // 按 token 推送，最多1000个
+	Topic        string         `json:"topic,omitempty"`     // 按主题推送
+	Condition    string         `json:"condition,omitempty"` // 按条件推送
+}
+
+type Notification struct {
+	Title string `json:"title,omitempty"`
+	Body  string `json:"body,omitempty"`
+	Image string `json:"image,omitempty"`
+}
+
+type AndroidConfig struct {
+	CollapseKey  int                  `json:"collapse_key,omitempty"`
+	Urgency      string               `json:"urgency,omitempty"`  // HIGH 或 NORMAL
+	Category     string               `json:"category,omitempty"` // 消息自分类
+	TTL          string               `json:"ttl,omitempty"`      // 如 "86400s"
+	BiTag        string               `json:"bi_tag,omitempty"`   // 回执中携带的批量任务标识
+	Data         string               `json:"data,omitempty"`
+	Notification *AndroidNotification `json:"notification,omitempty"`
+	Receipt      *ReceiptConfig       `json:"receipt,omitempty"`
+}
+
+type AndroidNotification struct {
+	Title       string       `json:"title,omitempty"`
+	Body        string       `json:"body,omitempty"`
+	Icon        string       `json:"icon,omitempty"`
+	Color       string       `json:"color,omitempty"`
+	Sound       string       `json:"sound,omitempty"`
+	Tag         string       `json:"tag,omitempty"`
+	ClickAction *ClickAction `json:"click_action,omitempty"`
+	ChannelId   string       `json:"channel_id,omitempty"`
+	Style       int          `json:"style,omitempty"` // 0 默认样式, 1 大文本样式, 3 多行文本样式
+	BigTitle    string       `json:"big_title,omitempty"`
+	BigBody     string       `json:"big_body,omitempty"`
+	AutoClear   int          `json:"auto_clear,omitempty"`
+	NotifyId    int          `json:"notify_id,omitempty"`
+	Importance  string       `json:"importance,omitempty"` // LOW 或 NORMAL
+	Ticker      string       `json:"ticker,omitempty"`
+	Visibility  string       `json:"visibility,omitempty"`
+}
+
+type ClickAction struct {
+	Type   int    `json:"type"`             // 1 打开应用自定义页面, 2 打开URL, 3 打开应用
+	Intent string `json:"intent,omitempty"` // type 为 1 时的 intent
+	Action string `json:"action,omitempty"` // type 为 1 时的 action
+	Url    string `json:"url,omitempty"`    // type 为 2 时的 URL
+}
+
+type ReceiptConfig struct {
+	ReceiptId string `json:"receipt_id,omitempty"` // 回执 ID，在华为开发者联盟配置
+}
+
+type MessageResponse struct {
+	Code      string `json:"code"` // "80000000" 表示成功
+	Msg       string `json:"msg"`
+	RequestId string `json:"requestId"`
+}
+
+type TokenInfo struct {
+	ClientId     string
+	ClientSecret string
+	AuthURL      string
+
+	Token      string
+	ExpireTime time.Time
+}
+
+type TokenResp struct {
+	AccessToken      string `json:"access_token"`
+	ExpiresIn        int64  `json:"expires_in"` // 有效时长，单位秒
+	TokenType        string `json:"token_type"`
+	Error            int    `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+type client struct {
+	hw         sdk.Huawei
+	client     *http.HTTPClient
+	authClient *http.AuthClient
+}
+
+func NewHuaweiClient(hw sdk.Huawei) (*client, error) {
+	if hw.AppId == "" {
+		return nil, errors.New("app id empty")
+	}
+	if hw.AppSecret == "" {
+		return nil, errors.New("app secret empty")
+	}
+	SetDefaults(&hw)
+
+	hc := http.NewSecureHTTPClient()
+	return &client{
+		hw:     hw,
+		client: hc,
+		authClient: http.NewAuthClientWith(&TokenInfo{
+			ClientId:     hw.AppId,
+			ClientSecret: hw.AppSecret,
+			AuthURL:      hw.AuthURL,
+		}, hc),
+	}, nil
+}
+
+// SetDefaults 为未填写的接口地址填充官方默认地址，推送地址依赖 AppId
+func SetDefaults(hw *sdk.Huawei) {
+	if hw.PushURL == "" && hw.AppId != "" {
+		hw.PushURL = fmt.Sprintf(DefaultPushURL, hw.AppId)
+	}
+	if hw.AuthURL == "" {
+		hw.AuthURL = DefaultAuthURL
+	}
+}
+
+func (c *client) Notify(ctx context.Context, req sdk.MessageRequest) (sdk.MessageResponse, error) {
+	if e := req.Validate(); e != nil {
+		return nil, e
+	}
+
+	token, err := c.authClient.GetAuthToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return nil, errors.New("can not get token")
+	}
+
+	data, err := req.GetRequestBody()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Do(ctx, &http.PushRequest{
+		Method: "POST",
+		URL:    c.hw.PushURL,
+		Body:   data,
+		Header: []http.HTTPOption{
+			http.SetHeader("Content-Type", "application/json;charset=utf-8"),
+			http.SetHeader("Authorization", "Bearer "+token),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status != 200 {
+		return nil, errors.New(fmt.Sprintf("notify request failed %s", string(resp.Body)))
+	}
+
+	var r MessageResponse
+	err = json.Unmarshal(resp.Body, &r)
+	if err != nil {
+		return nil, err
+	}
+	if r.Code != successCode && r.Code != partialSuccessCode {
+		return nil, errors.New(fmt.Sprintf("notify request failed code %s %s", r.Code, r.Msg))
+	}
+
+	return &r, nil
+}
+
+func (m *MessageRequest) Validate() error {
+	msg := m.Message
+	targets := 0
+	if len(msg.Token) > 0 {
+		targets++
+	}
+	if msg.Topic != "" {
+		targets++
+	}
+	if msg.Condition != "" {
+		targets++
+	}
+	if targets != 1 {
+		return errors.New("exactly one of token, topic and condition must be set")
+	}
+	if len(msg.Token) > 1000 {
+		return errors.New("too many tokens, at most 1000")
+	}
+	if msg.Data == "" && msg.Notification == nil && (msg.Android == nil || msg.Android.Notification == nil) {
+		return errors.New("message data and notification are both empty")
+	}
+	if msg.Android != nil && msg.Android.Notification != nil {
+		action := msg.Android.Notification.ClickAction
+		if action == nil {
+			return errors.New("android notification click action is empty")
+		}
+		switch action.Type {
+		case 1:
+			if action.Intent == "" && action.Action == "" {
+				return errors.New("click action intent or action is empty")
+			}
+		case 2:
+			if action.Url == "" {
+				return errors.New("click action url is empty")
+			}
+		case 3:
+		default:
+			return errors.New("wrong click action type")
+		}
+	}
+	return nil
+}
+
+func (m *MessageRequest) GetRequestBody() ([]byte, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// err 判断华为是否拒绝了推送；单个 token 时部分成功即表示该 token 推送失败
+func (m *MessageResponse) err() error {
+	if m.Code == successCode {
+		return nil
+	}
+	return errors.New(fmt.Sprintf("notify request failed code %s %s", m.Code, m.Msg))
+}
+
+func (m *MessageResponse) GetResult() string {
+	return m.Code
+}
+
+func (m *MessageResponse) GetData() map[string]string {
+	return map[string]string{
+		"requestId": m.RequestId,
+	}
+}
+
+func (t *TokenInfo) TokenRequest() ([]byte, error) {
+	data := url.Values{}
+	data.Add("grant_type", "client_credentials")
+	data.Add("client_id", t.ClientId)
+	data.Add("client_secret", t.ClientSecret)
+	return []byte(data.Encode()), nil
+}
+
+func (t *TokenInfo) ParseResponse(d []byte) error {
+	var token TokenResp
+	err := json.Unmarshal(d, &token)
+	if err != nil {
+		return err
+	}
+	if token.AccessToken == "" {
+		return errors.New(fmt.Sprintf("get access token failed %d %s", token.Error, token.ErrorDescription))
+	}
+
+	t.Token = token.AccessToken
+	t.ExpireTime = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return nil
+}
+
+func (t *TokenInfo) SetHeader() []http.HTTPOption {
+	return []http.HTTPOption{
+		http.SetHeader("Content-Type", "application/x-www-form-urlencoded"),
+	}
+}
+
+func (t *TokenInfo) GetAuthUrl() string {
+	return t.AuthURL
+}
+
+func (t *TokenInfo) GetAuthMethod() string {
+	return "POST"
+}
+
+func (t *TokenInfo) GetAccessToken() string {
+	return t.Token
+}
+
+func (t *TokenInfo) IsValidate() bool {
+	return t.Token != "" && time.Now().Add(tokenRefreshAhead).Before(t.ExpireTime)
+}
+
+// FromMessage 将通用消息转换为发送到指定 token 的华为通知栏消息，点击打开应用；
+// 自定义参数放入 data，回执参数作为 biTag 随回执返回。华为通知没有副标题，回执地址需在
+// 华为控制台配置，因此 SubTitle 与 CallBack 不被支持
+func FromMessage(msg *sdk.Message, token string) (*MessageRequest, error) {
+	req := &MessageRequest{
+		Message: Message{
+			Token: []string{token},
+			Notification: &Notification{
+				Title: msg.Title,
+				Body:  msg.Content,
+			},
+			Android: &AndroidConfig{
+				BiTag: msg.CallbackParam,
+				Notification: &AndroidNotification{
+					ClickAction: &ClickAction{Type: 3},
+				},
+			},
+		},
+	}
+	if len(msg.Extra) > 0 {
+		data, err := json.Marshal(msg.Extra)
+		if err != nil {
+			return nil, err
+		}
+		req.Message.Data = string(data)
+	}
+	return req, nil
+}
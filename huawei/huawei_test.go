@@ -0,0 +1,115 @@
+package huawei
+
+import (
+	"context"
+	"encoding/json"
+	sdk "github.com/holicc/push-sdk"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHuaweiNotify(t *testing.T) {
+	tokenRequests := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth2/v3/token", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.PostForm.Get("grant_type") != "client_credentials" || r.PostForm.Get("client_id") != "10086" {
+			t.Errorf("unexpected token request %v", r.PostForm)
+		}
+		w.Write([]byte(`{"access_token":"token-1","expires_in":3600,"token_type":"Bearer"}`))
+	})
+	mux.HandleFunc("/v1/10086/messages:send", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			t.Errorf("unexpected authorization %s", r.Header.Get("Authorization"))
+		}
+		var req MessageRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if len(req.Message.Token) != 1 || req.Message.Token[0] != "device-token" {
+			t.Errorf("unexpected message %v", req.Message)
+		}
+		w.Write([]byte(`{"code":"80000000","msg":"Success","requestId":"r-1"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	hwClient, err := NewHuaweiClient(sdk.Huawei{
+		Platform: sdk.Platform{
+			PushURL: server.URL + "/v1/10086/messages:send",
+			AuthURL: server.URL + "/oauth2/v3/token",
+		},
+		AppId:     "10086",
+		AppSecret: "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &MessageRequest{
+		Message: Message{
+			Token:        []string{"device-token"},
+			Notification: &Notification{Title: "Hello", Body: "Hello World"},
+			Android: &AndroidConfig{
+				Notification: &AndroidNotification{ClickAction: &ClickAction{Type: 3}},
+			},
+		},
+	}
+	for i := 0; i < 2; i++ {
+		notify, err := hwClient.Notify(context.Background(), req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if notify.GetResult() != successCode {
+			t.Errorf("unexpected result %s", notify.GetResult())
+		}
+	}
+	if tokenRequests != 1 {
+		t.Errorf("token should be cached, requested %d times", tokenRequests)
+	}
+}
+
+func TestTokenExpiresIn(t *testing.T) {
+	token := &TokenInfo{}
+	if err := token.ParseResponse([]byte(`{"access_token":"a","expires_in":60}`)); err != nil {
+		t.Fatal(err)
+	}
+	if token.IsValidate() {
+		t.Error("token expiring within the refresh window should not be valid")
+	}
+	if err := token.ParseResponse([]byte(`{"access_token":"a","expires_in":3600}`)); err != nil {
+		t.Fatal(err)
+	}
+	if !token.IsValidate() || token.ExpireTime.Before(time.Now().Add(59*time.Minute)) {
+		t.Error("token should be valid for expires_in seconds")
+	}
+	if err := token.ParseResponse([]byte(`{"error":1101,"error_description":"invalid request"}`)); err == nil {
+		t.Error("expected error for failed token response")
+	}
+}
+
+func TestFromMessage(t *testing.T) {
+	req, err := FromMessage(&sdk.Message{
+		Title:         "Hello",
+		Content:       "Hello World",
+		Extra:         map[string]string{"orderId": "42"},
+		CallbackParam: "biz-1",
+	}, "token-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	m := req.Message
+	if len(m.Token) != 1 || m.Token[0] != "token-1" || m.Notification.Title != "Hello" || m.Notification.Body != "Hello World" {
+		t.Errorf("unexpected message %+v", m)
+	}
+	if m.Data != `{"orderId":"42"}` || m.Android.BiTag != "biz-1" || m.Android.Notification.ClickAction.Type != 3 {
+		t.Errorf("unexpected android config %+v data %s", m.Android, m.Data)
+	}
+}
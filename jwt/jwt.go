@@ -0,0 +1,110 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+)
+
+// SignES256 使用 P-256 私钥签发 JWT，签名为 r||s 定长编码
+func SignES256(key *ecdsa.PrivateKey, header, claims map[string]interface{}) (string, error) {
+	signingInput, err := encodeSegments("ES256", header, claims)
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256([]byte(signingInput))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	size := (key.Curve.Params().BitSize + 7) / 8
+	sig := make([]byte, 2*size)
+	copyPadded(sig[:size], r)
+	copyPadded(sig[size:], s)
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// SignRS256 使用 RSA 私钥签发 JWT，如 Google 服务账号的授权断言
+func SignRS256(key *rsa.PrivateKey, header, claims map[string]interface{}) (string, error) {
+	signingInput, err := encodeSegments("RS256", header, claims)
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+// ParseECPrivateKey 解析 PEM 格式的 PKCS#8 或 SEC1 EC 私钥，如 APNs 的 .p8 文件
+func ParseECPrivateKey(data []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid pem private key")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		ecKey, ok := key.(*ecdsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("private key is not an ecdsa key")
+		}
+		return ecKey, nil
+	}
+	return x509.ParseECPrivateKey(block.Bytes)
+}
+
+// ParseRSAPrivateKey 解析 PEM 格式的 PKCS#8 或 PKCS#1 RSA 私钥
+func ParseRSAPrivateKey(data []byte) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid pem private key")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.New("private key is not a rsa key")
+		}
+		return rsaKey, nil
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+func encodeSegments(alg string, header, claims map[string]interface{}) (string, error) {
+	h := map[string]interface{}{
+		"alg": alg,
+	}
+	for k, v := range header {
+		h[k] = v
+	}
+
+	headerJson, err := json.Marshal(h)
+	if err != nil {
+		return "", err
+	}
+	claimsJson, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(headerJson) + "." + base64.RawURLEncoding.EncodeToString(claimsJson), nil
+}
+
+func copyPadded(dst []byte, n *big.Int) {
+	b := n.Bytes()
+	copy(dst[len(dst)-len(b):], b)
+}
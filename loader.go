@@ -0,0 +1,275 @@
+package push_sdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultEnvPrefix 为环境变量前缀，如 PUSH_XIAOMI_APPSECRET
+	DefaultEnvPrefix = "PUSH"
+
+	filePrefix = "file:"
+	envPrefix  = "env:"
+)
+
+// Load 读取配置文件（path 为空时跳过），再以 PUSH_ 前缀的环境变量覆盖，
+// 解析 file:/env: 引用后校验，三步的问题汇总在同一个 *ConfigError 中
+func Load(path string) (*PushConfig, error) {
+	cfg := &PushConfig{}
+	if path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml":
+			err = unmarshalYAML(data, cfg)
+		case ".json":
+			err = json.Unmarshal(data, cfg)
+		default:
+			err = errors.New(fmt.Sprintf("unsupported config file %s", path))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return resolveAndValidate(cfg, ApplyEnv(cfg, DefaultEnvPrefix))
+}
+
+// LoadJSON 从 JSON 内容解析配置，解析 file:/env: 引用后校验
+func LoadJSON(data []byte) (*PushConfig, error) {
+	cfg := &PushConfig{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+	return resolveAndValidate(cfg)
+}
+
+// LoadYAML 从 YAML 内容解析配置，字段名与 JSON 一致
+func LoadYAML(data []byte) (*PushConfig, error) {
+	cfg := &PushConfig{}
+	if err := unmarshalYAML(data, cfg); err != nil {
+		return nil, err
+	}
+	return resolveAndValidate(cfg)
+}
+
+// LoadEnv 仅从环境变量构建配置
+func LoadEnv(prefix string) (*PushConfig, error) {
+	cfg := &PushConfig{}
+	return resolveAndValidate(cfg, ApplyEnv(cfg, prefix))
+}
+
+// resolveAndValidate 解析 file:/env: 引用并校验，将 envErr 与两步的问题汇总到同一个 *ConfigError
+func resolveAndValidate(cfg *PushConfig, envErr ...error) (*PushConfig, error) {
+	var cfgErr ConfigError
+	for _, err := range envErr {
+		cfgErr.merge(err)
+	}
+	cfgErr.merge(cfg.ResolveSecrets())
+	cfgErr.merge(cfg.Validate())
+	if err := cfgErr.orNil(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// unmarshalYAML 先将 YAML 转为 JSON，复用 PushConfig 的 json 标签；
+// 字符串字段的标量按原文解析，使 appId: 100123 这类未加引号的数字也能填入
+func unmarshalYAML(data []byte, cfg *PushConfig) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	if root.Kind == yaml.MappingNode {
+		sections := reflect.ValueOf(cfg).Elem()
+		for i := 0; i < sections.NumField(); i++ {
+			if node := mappingValue(root, jsonName(sections.Type().Field(i))); node != nil {
+				tagStrings(node, sections.Field(i))
+			}
+		}
+	}
+
+	var raw interface{}
+	if err := root.Decode(&raw); err != nil {
+		return err
+	}
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, cfg)
+}
+
+// tagStrings 将 sv 中字符串字段对应的 YAML 标量标记为 !!str
+func tagStrings(node *yaml.Node, sv reflect.Value) {
+	walkStruct(sv, func(field reflect.StructField, v reflect.Value) {
+		if v.Kind() != reflect.String {
+			return
+		}
+		if value := mappingValue(node, jsonName(field)); value != nil && value.Kind == yaml.ScalarNode && value.Tag != "!!null" {
+			value.Tag = "!!str"
+		}
+	})
+}
+
+// mappingValue 返回 YAML 映射中 key 对应的值，node 不是映射或不存在 key 时返回 nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// ApplyEnv 以 <PREFIX>_<厂商>_<字段> 形式的环境变量覆盖配置，名称取自 json 标签的大写，
+// 如 PUSH_XIAOMI_APPSECRET、PUSH_OPPO_PUSHURL
+func ApplyEnv(cfg *PushConfig, prefix string) error {
+	var cfgErr ConfigError
+	walkFields(cfg, func(section string, field reflect.StructField, v reflect.Value) {
+		name := strings.ToUpper(prefix + "_" + section + "_" + jsonName(field))
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+		switch v.Kind() {
+		case reflect.String:
+			v.SetString(value)
+		case reflect.Bool:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				cfgErr.add("%s: invalid bool %q", name, value)
+				return
+			}
+			v.SetBool(b)
+		case reflect.Int:
+			n, err := strconv.ParseInt(value, 10, 0)
+			if err != nil {
+				cfgErr.add("%s: invalid int %q", name, value)
+				return
+			}
+			v.SetInt(n)
+		}
+	})
+	return cfgErr.orNil()
+}
+
+// ResolveSecrets 将 "file:<路径>" 与 "env:<变量名>" 形式的字段替换为文件内容或环境变量的值
+func (c *PushConfig) ResolveSecrets() error {
+	var cfgErr ConfigError
+	walkFields(c, func(section string, field reflect.StructField, v reflect.Value) {
+		if v.Kind() != reflect.String {
+			return
+		}
+		value := v.String()
+		switch {
+		case strings.HasPrefix(value, filePrefix):
+			data, err := ioutil.ReadFile(strings.TrimPrefix(value, filePrefix))
+			if err != nil {
+				cfgErr.add("%s.%s: %v", section, jsonName(field), err)
+				return
+			}
+			v.SetString(strings.TrimRight(string(data), "\r\n"))
+		case strings.HasPrefix(value, envPrefix):
+			name := strings.TrimPrefix(value, envPrefix)
+			env, ok := os.LookupEnv(name)
+			if !ok {
+				cfgErr.add("%s.%s: environment variable %s not set", section, jsonName(field), name)
+				return
+			}
+			v.SetString(env)
+		}
+	})
+	return cfgErr.orNil()
+}
+
+// Validate 检查已填写厂商的必填字段与接口、主机地址格式，返回包含全部问题的 *ConfigError；
+// 厂商的任一必填字段有值即视为已填写
+func (c *PushConfig) Validate() error {
+	var cfgErr ConfigError
+
+	sections := reflect.ValueOf(c).Elem()
+	for i := 0; i < sections.NumField(); i++ {
+		section := jsonName(sections.Type().Field(i))
+		sv := sections.Field(i)
+
+		var missing []string
+		configured := false
+		walkStruct(sv, func(field reflect.StructField, v reflect.Value) {
+			if field.Tag.Get("required") != "true" {
+				return
+			}
+			if v.String() == "" {
+				missing = append(missing, jsonName(field))
+			} else {
+				configured = true
+			}
+		})
+		if !configured {
+			continue
+		}
+		for _, name := range missing {
+			cfgErr.add("%s.%s is required", section, name)
+		}
+		walkStruct(sv, func(field reflect.StructField, v reflect.Value) {
+			isAddress := strings.HasSuffix(field.Name, "URL") || strings.HasSuffix(field.Name, "Host")
+			if !isAddress || v.String() == "" {
+				return
+			}
+			u, err := url.Parse(v.String())
+			if err != nil || u.Scheme == "" || u.Host == "" {
+				cfgErr.add("%s.%s is not an absolute url", section, jsonName(field))
+			}
+		})
+	}
+	return cfgErr.orNil()
+}
+
+func walkFields(cfg *PushConfig, fn func(section string, field reflect.StructField, v reflect.Value)) {
+	sections := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < sections.NumField(); i++ {
+		section := jsonName(sections.Type().Field(i))
+		walkStruct(sections.Field(i), func(field reflect.StructField, v reflect.Value) {
+			fn(section, field, v)
+		})
+	}
+}
+
+// walkStruct 遍历结构体的字段，匿名嵌入的结构体（如 Platform）展开处理
+func walkStruct(sv reflect.Value, fn func(field reflect.StructField, v reflect.Value)) {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			walkStruct(sv.Field(i), fn)
+			continue
+		}
+		fn(field, sv.Field(i))
+	}
+}
+
+func jsonName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
@@ -0,0 +1,165 @@
+package push_sdk
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadYAMLWithSecretReferences(t *testing.T) {
+	dir, err := ioutil.TempDir("", "push-sdk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	secretFile := filepath.Join(dir, "oppo_secret")
+	if err := ioutil.WriteFile(secretFile, []byte("master-secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("TEST_XIAOMI_SECRET", "xiaomi-secret")
+	defer os.Unsetenv("TEST_XIAOMI_SECRET")
+
+	cfg, err := LoadYAML([]byte(`
+xiaomi:
+  appPkgName: com.example
+  appSecret: env:TEST_XIAOMI_SECRET
+oppo:
+  appKey: key
+  masterSecret: file:` + secretFile + `
+  pushURL: https://example.com/unicast
+apns:
+  sandbox: true
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.XiaoMi.AppSecret != "xiaomi-secret" {
+		t.Errorf("unexpected xiaomi secret %s", cfg.XiaoMi.AppSecret)
+	}
+	if cfg.Oppo.MasterSecret != "master-secret" || cfg.Oppo.PushURL != "https://example.com/unicast" {
+		t.Errorf("unexpected oppo config %v", cfg.Oppo)
+	}
+	if !cfg.APNs.Sandbox {
+		t.Error("apns sandbox should be set")
+	}
+}
+
+func TestLoadEnv(t *testing.T) {
+	os.Setenv("TESTPUSH_VIVO_APPID", "1")
+	os.Setenv("TESTPUSH_VIVO_APPKEY", "key")
+	os.Setenv("TESTPUSH_VIVO_APPSECRET", "secret")
+	os.Setenv("TESTPUSH_VIVO_PUSHURL", "https://example.com/send")
+	defer func() {
+		for _, name := range []string{"TESTPUSH_VIVO_APPID", "TESTPUSH_VIVO_APPKEY", "TESTPUSH_VIVO_APPSECRET", "TESTPUSH_VIVO_PUSHURL"} {
+			os.Unsetenv(name)
+		}
+	}()
+
+	cfg, err := LoadEnv("TESTPUSH")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Vivo.AppId != "1" || cfg.Vivo.AppSecret != "secret" || cfg.Vivo.PushURL != "https://example.com/send" {
+		t.Errorf("unexpected vivo config %v", cfg.Vivo)
+	}
+}
+
+func TestValidateReportsAllProblems(t *testing.T) {
+	_, err := LoadJSON([]byte(`{
+		"xiaomi": {"appPkgName": "com.example"},
+		"vivo": {"appId": "1", "pushURL": "not a url"},
+		"meizu": {"appPkgName": "com.example"},
+		"fcm": {"serviceAccount": "env:TEST_MISSING_SERVICE_ACCOUNT"}
+	}`))
+	cfgErr, ok := err.(*ConfigError)
+	if !ok {
+		t.Fatalf("expected *ConfigError, got %v", err)
+	}
+	expected := []string{
+		"fcm.serviceAccount: environment variable TEST_MISSING_SERVICE_ACCOUNT not set",
+		"xiaomi.appSecret is required",
+		"vivo.appKey is required",
+		"vivo.appSecret is required",
+		"vivo.pushURL is not an absolute url",
+	}
+	if strings.Join(cfgErr.Problems, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected problems %v, got %v", expected, cfgErr.Problems)
+	}
+
+	_, err = LoadJSON([]byte(`{
+		"xiaomi": {"appPkgName": "com.example"},
+		"vivo": {"appId": "1", "pushURL": "not a url"},
+		"meizu": {"appPkgName": "com.example"}
+	}`))
+	cfgErr, ok = err.(*ConfigError)
+	if !ok {
+		t.Fatalf("expected *ConfigError, got %v", err)
+	}
+	// xiaomi.appSecret, vivo.appKey, vivo.appSecret, vivo.pushURL；meizu 未填写必填字段，不做校验
+	if len(cfgErr.Problems) != 4 {
+		t.Errorf("expected 4 problems, got %v", cfgErr.Problems)
+	}
+}
+
+func TestApplyEnvInt(t *testing.T) {
+	os.Setenv("TESTPUSH_OPPO_NOTIFYLEVEL", "16")
+	defer os.Unsetenv("TESTPUSH_OPPO_NOTIFYLEVEL")
+
+	cfg := &PushConfig{}
+	if err := ApplyEnv(cfg, "TESTPUSH"); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Oppo.NotifyLevel != 16 {
+		t.Errorf("unexpected oppo notify level %d", cfg.Oppo.NotifyLevel)
+	}
+
+	os.Setenv("TESTPUSH_OPPO_NOTIFYLEVEL", "high")
+	err := ApplyEnv(cfg, "TESTPUSH")
+	cfgErr, ok := err.(*ConfigError)
+	if !ok {
+		t.Fatalf("expected *ConfigError, got %v", err)
+	}
+	if len(cfgErr.Problems) != 1 || cfgErr.Problems[0] != `TESTPUSH_OPPO_NOTIFYLEVEL: invalid int "high"` {
+		t.Errorf("unexpected problems %v", cfgErr.Problems)
+	}
+}
+
+func TestValidateHost(t *testing.T) {
+	_, err := LoadJSON([]byte(`{
+		"meizu": {"appId": "1", "appSecret": "secret", "host": "https://server-api-push.meizu.com"},
+		"apns": {"bundleId": "com.example", "teamId": "team", "keyId": "key", "authKey": "pem", "host": "api.push.apple.com"}
+	}`))
+	cfgErr, ok := err.(*ConfigError)
+	if !ok {
+		t.Fatalf("expected *ConfigError, got %v", err)
+	}
+	if len(cfgErr.Problems) != 1 || cfgErr.Problems[0] != "apns.host is not an absolute url" {
+		t.Errorf("unexpected problems %v", cfgErr.Problems)
+	}
+}
+
+func TestLoadYAMLNumericStrings(t *testing.T) {
+	cfg, err := LoadYAML([]byte(`
+vivo:
+  appId: 100123
+  appKey: 0123
+  appSecret: secret
+oppo:
+  appKey: key
+  masterSecret: secret
+  notifyLevel: 2
+  category: IM
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Vivo.AppId != "100123" || cfg.Vivo.AppKey != "0123" {
+		t.Errorf("unexpected vivo config %v", cfg.Vivo)
+	}
+	if cfg.Oppo.NotifyLevel != 2 {
+		t.Errorf("unexpected oppo notify level %d", cfg.Oppo.NotifyLevel)
+	}
+}
@@ -0,0 +1,43 @@
+package meizu
+
+import (
+	sdk "github.com/holicc/push-sdk"
+)
+
+func init() {
+	sdk.Register(sdk.VendorMeiZu, driver{})
+}
+
+type driver struct{}
+
+func (driver) Configured(cfg *sdk.PushConfig) bool {
+	return cfg.MeiZu.AppId != "" ||
+		cfg.MeiZu.AppSecret != ""
+}
+
+func (driver) SetDefaults(cfg *sdk.PushConfig) {
+	SetDefaults(&cfg.MeiZu)
+}
+
+func (driver) NewClient(cfg *sdk.PushConfig) (sdk.PushClient, error) {
+	c, err := NewMeiZuClient(cfg.MeiZu)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (driver) Convert(msg *sdk.Message, token string) (sdk.MessageRequest, error) {
+	req, err := FromMessage(msg, token)
+	if err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (driver) Check(resp sdk.MessageResponse) error {
+	if r, ok := resp.(*MessageResponse); ok {
+		return r.err()
+	}
+	return nil
+}
@@ -0,0 +1,310 @@
+package meizu
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	sdk "github.com/holicc/push-sdk"
+	"github.com/holicc/push-sdk/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	DefaultHost = "https://server-api-push.meizu.com"
+
+	notificationPath = "/garcia/api/server/push/varnished/pushByPushId"
+	passThroughPath  = "/garcia/api/server/push/unvarnished/pushByPushId"
+
+	maxPushIds = 1000
+)
+
+type MessageRequest struct {
+	PushIds       []string          `json:"-"`          // 推送目标 pushId，最多1000个
+	PassThrough   int               `json:"-"`          // 0 表示通知栏消息,1 表示透传消息
+	Title         string            `json:"title"`      // 通知栏标题，不超过32个字符
+	Content       string            `json:"content"`    // 通知栏内容，不超过100个字符
+	ClickType     int               `json:"clickType"`  // 0 打开应用, 1 打开应用页面, 2 打开URI页面, 3 应用客户端自定义
+	Url           string            `json:"url"`        // clickType 为 2 时的 URI
+	Activity      string            `json:"activity"`   // clickType 为 1 时的应用页面
+	Parameters    map[string]string `json:"parameters"` // 透传给应用的参数
+	OffLine       int               `json:"offLine"`    // 是否进离线消息 0 否, 1 是
+	ValidTime     int               `json:"validTime"`  // 有效时长，单位小时，1-72
+	CallBack      string            `json:"callback"`   // 送达回执地址
+	CallBackParam string            `json:"callbackParam"`
+	CallBackType  int               `json:"callbackType"` // 1 送达回执, 2 点击回执, 3 送达与点击回执
+}
+
+type MessageResponse struct {
+	Code     string        `json:"code"`    // "200" 表示成功
+	Message  string        `json:"message"` // 错误信息
+	Value    *MessageValue `json:"value"`
+	Redirect string        `json:"redirect"`
+}
+
+type MessageValue struct {
+	MsgId      string              `json:"msgId"`      // 推送消息ID
+	RespTarget map[string][]string `json:"respTarget"` // 错误码对应的推送失败的 pushId
+	Logs       map[string]string   `json:"logs"`
+}
+
+type noticeBarInfo struct {
+	NoticeBarType int    `json:"noticeBarType"`
+	Title         string `json:"title"`
+	Content       string `json:"content"`
+}
+
+type clickTypeInfo struct {
+	ClickType  int               `json:"clickType"`
+	Url        string            `json:"url,omitempty"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+	Activity   string            `json:"activity,omitempty"`
+}
+
+type pushTimeInfo struct {
+	OffLine   int `json:"offLine"`
+	ValidTime int `json:"validTime"`
+}
+
+type callbackInfo struct {
+	CallBack      string `json:"callback,omitempty"`
+	CallBackParam string `json:"callback.param,omitempty"`
+	CallBackType  int    `json:"callback.type,omitempty"`
+}
+
+type notificationMessage struct {
+	NoticeBarInfo noticeBarInfo `json:"noticeBarInfo"`
+	ClickTypeInfo clickTypeInfo `json:"clickTypeInfo"`
+	PushTimeInfo  pushTimeInfo  `json:"pushTimeInfo"`
+	Extra         *callbackInfo `json:"extra,omitempty"`
+}
+
+type passThroughMessage struct {
+	Title        string       `json:"title"`
+	Content      string       `json:"content"`
+	PushTimeInfo pushTimeInfo `json:"pushTimeInfo"`
+}
+
+type client struct {
+	mz     sdk.MeiZu
+	client *http.HTTPClient
+}
+
+func NewMeiZuClient(mz sdk.MeiZu) (*client, error) {
+	if mz.AppId == "" {
+		return nil, errors.New("app id empty")
+	}
+	if mz.AppSecret == "" {
+		return nil, errors.New("app secret empty")
+	}
+	SetDefaults(&mz)
+
+	return &client{
+		mz:     mz,
+		client: http.NewSecureHTTPClient(),
+	}, nil
+}
+
+// SetDefaults 为未填写的主机地址填充官方默认地址
+func SetDefaults(mz *sdk.MeiZu) {
+	if mz.Host == "" {
+		mz.Host = DefaultHost
+	}
+}
+
+func (c *client) Notify(ctx context.Context, req sdk.MessageRequest) (sdk.MessageResponse, error) {
+	if e := req.Validate(); e != nil {
+		return nil, e
+	}
+	r, ok := req.(*MessageRequest)
+	if !ok {
+		return nil, errors.New("unsupported message request type")
+	}
+
+	messageJson, err := r.GetRequestBody()
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]string{
+		"appId":       c.mz.AppId,
+		"pushIds":     strings.Join(r.PushIds, ","),
+		"messageJson": string(messageJson),
+	}
+	params["sign"] = generateSign(params, c.mz.AppSecret)
+
+	data := url.Values{}
+	for key, value := range params {
+		data.Add(key, value)
+	}
+
+	path := notificationPath
+	if r.PassThrough == 1 {
+		path = passThroughPath
+	}
+
+	resp, err := c.client.Do(ctx, &http.PushRequest{
+		Method: "POST",
+		URL:    strings.TrimRight(c.mz.Host, "/") + path,
+		Body:   []byte(data.Encode()),
+		Header: []http.HTTPOption{
+			http.SetHeader("Content-Type", "application/x-www-form-urlencoded;charset=UTF-8"),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Status != 200 {
+		return nil, errors.New(fmt.Sprintf("notify request failed %s", string(resp.Body)))
+	}
+
+	var mr MessageResponse
+	err = json.Unmarshal(resp.Body, &mr)
+	if err != nil {
+		return nil, err
+	}
+	if mr.Code != "200" {
+		return nil, errors.New(fmt.Sprintf("notify request failed code %s %s", mr.Code, mr.Message))
+	}
+
+	return &mr, nil
+}
+
+func (m *MessageRequest) Validate() error {
+	if len(m.PushIds) == 0 {
+		return errors.New("push ids empty")
+	}
+	if len(m.PushIds) > maxPushIds {
+		return errors.New(fmt.Sprintf("too many push ids, at most %d", maxPushIds))
+	}
+	if m.PassThrough != 0 && m.PassThrough != 1 {
+		return errors.New("unknown message pass type")
+	}
+	if m.PassThrough == 0 && m.Title == "" {
+		return errors.New("message title is empty")
+	}
+	if m.Content == "" {
+		return errors.New("message content is empty")
+	}
+	if m.ClickType < 0 || m.ClickType > 3 {
+		return errors.New("wrong click type")
+	}
+	if m.ClickType == 1 && m.Activity == "" {
+		return errors.New("activity is empty")
+	}
+	if m.ClickType == 2 && m.Url == "" {
+		return errors.New("url is empty")
+	}
+	if m.ValidTime < 0 || m.ValidTime > 72 {
+		return errors.New("valid time must between 1 and 72 hours")
+	}
+	return nil
+}
+
+// GetRequestBody 返回 messageJson 参数的内容，appId、pushIds 与签名由客户端补充
+func (m *MessageRequest) GetRequestBody() ([]byte, error) {
+	timeInfo := pushTimeInfo{
+		OffLine:   m.OffLine,
+		ValidTime: m.ValidTime,
+	}
+	if timeInfo.ValidTime == 0 {
+		timeInfo.ValidTime = 24
+	}
+
+	if m.PassThrough == 1 {
+		return json.Marshal(&passThroughMessage{
+			Title:        m.Title,
+			Content:      m.Content,
+			PushTimeInfo: timeInfo,
+		})
+	}
+
+	msg := &notificationMessage{
+		NoticeBarInfo: noticeBarInfo{
+			Title:   m.Title,
+			Content: m.Content,
+		},
+		ClickTypeInfo: clickTypeInfo{
+			ClickType:  m.ClickType,
+			Url:        m.Url,
+			Parameters: m.Parameters,
+			Activity:   m.Activity,
+		},
+		PushTimeInfo: timeInfo,
+	}
+	if m.CallBack != "" {
+		msg.Extra = &callbackInfo{
+			CallBack:      m.CallBack,
+			CallBackParam: m.CallBackParam,
+			CallBackType:  m.CallBackType,
+		}
+		if msg.Extra.CallBackType == 0 {
+			msg.Extra.CallBackType = 3
+		}
+	}
+	return json.Marshal(msg)
+}
+
+// err 判断魅族是否拒绝了推送，code 为 "200" 时仍可能有 pushId 记录在 respTarget 中推送失败
+func (m *MessageResponse) err() error {
+	if m.Code != "200" {
+		return errors.New(fmt.Sprintf("notify request failed code %s %s", m.Code, m.Message))
+	}
+	if m.Value != nil && len(m.Value.RespTarget) > 0 {
+		return errors.New(fmt.Sprintf("notify failed push ids %v", m.Value.RespTarget))
+	}
+	return nil
+}
+
+func (m *MessageResponse) GetResult() string {
+	return m.Code
+}
+
+func (m *MessageResponse) GetData() map[string]string {
+	if m.Value == nil {
+		return nil
+	}
+	return map[string]string{
+		"msgId": m.Value.MsgId,
+	}
+}
+
+// generateSign 按参数名排序后拼接 key=value，末尾追加 appSecret 并计算 MD5
+func generateSign(params map[string]string, appSecret string) string {
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf strings.Builder
+	for _, key := range keys {
+		buf.WriteString(key)
+		buf.WriteString("=")
+		buf.WriteString(params[key])
+	}
+	buf.WriteString(appSecret)
+
+	hash := md5.New()
+	hash.Write([]byte(buf.String()))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// FromMessage 将通用消息转换为发送到指定 pushId 的魅族通知栏消息，点击打开应用；
+// 自定义参数放入 parameters。魅族通知没有副标题，也没有业务ID字段，因此 SubTitle 与 BusinessId 不被支持
+func FromMessage(msg *sdk.Message, pushId string) (*MessageRequest, error) {
+	return &MessageRequest{
+		PushIds:       []string{pushId},
+		Title:         msg.Title,
+		Content:       msg.Content,
+		ClickType:     0,
+		Parameters:    msg.Extra,
+		OffLine:       1,
+		CallBack:      msg.CallBack,
+		CallBackParam: msg.CallbackParam,
+	}, nil
+}
@@ -0,0 +1,91 @@
+package meizu
+
+import (
+	"context"
+	"encoding/json"
+	sdk "github.com/holicc/push-sdk"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMeiZuNotify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != notificationPath {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		params := map[string]string{
+			"appId":       r.PostForm.Get("appId"),
+			"pushIds":     r.PostForm.Get("pushIds"),
+			"messageJson": r.PostForm.Get("messageJson"),
+		}
+		if sign := generateSign(params, "secret"); sign != r.PostForm.Get("sign") {
+			t.Errorf("sign mismatch, want %s got %s", sign, r.PostForm.Get("sign"))
+		}
+		if params["pushIds"] != "id1,id2" {
+			t.Errorf("unexpected push ids %s", params["pushIds"])
+		}
+		var msg notificationMessage
+		if err := json.Unmarshal([]byte(params["messageJson"]), &msg); err != nil {
+			t.Fatal(err)
+		}
+		if msg.NoticeBarInfo.Title != "Hello" || msg.PushTimeInfo.ValidTime != 24 {
+			t.Errorf("unexpected message json %s", params["messageJson"])
+		}
+		w.Write([]byte(`{"code":"200","message":"","value":{"msgId":"NS001","respTarget":{}},"redirect":""}`))
+	}))
+	defer server.Close()
+
+	mzClient, err := NewMeiZuClient(sdk.MeiZu{
+		Host:      server.URL,
+		AppId:     "100",
+		AppSecret: "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notify, err := mzClient.Notify(context.Background(), &MessageRequest{
+		PushIds: []string{"id1", "id2"},
+		Title:   "Hello",
+		Content: "Hello World",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notify.GetResult() != "200" || notify.GetData()["msgId"] != "NS001" {
+		t.Errorf("unexpected response %v", notify)
+	}
+}
+
+func TestGenerateSign(t *testing.T) {
+	sign := generateSign(map[string]string{"b": "2", "a": "1"}, "sec")
+	// md5("a=1b=2sec")
+	if sign != "84b9ffccbe49f4079f0893d62de29ecf" {
+		t.Errorf("unexpected sign %s", sign)
+	}
+}
+
+func TestFromMessage(t *testing.T) {
+	req, err := FromMessage(&sdk.Message{
+		Title:         "Hello",
+		Content:       "Hello World",
+		Extra:         map[string]string{"orderId": "42"},
+		CallBack:      "https://example.com/callback",
+		CallbackParam: "biz-1",
+	}, "push-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if len(req.PushIds) != 1 || req.PushIds[0] != "push-id" || req.Title != "Hello" || req.Content != "Hello World" {
+		t.Errorf("unexpected request %+v", req)
+	}
+	if req.Parameters["orderId"] != "42" || req.CallBack != "https://example.com/callback" || req.CallBackParam != "biz-1" {
+		t.Errorf("unexpected request %+v", req)
+	}
+}
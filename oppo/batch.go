@@ -0,0 +1,123 @@
+package oppo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+const maxBatchMessages = 1000
+
+// BatchResult 为 unicast_batch 中单个目标的发送结果，顺序与传入的消息一致
+type BatchResult struct {
+	TargetValue  string // 消息的 target_value
+	MessageId    string // OPPO 返回的消息ID
+	ErrorCode    int    // OPPO 返回的错误码，0 表示成功
+	ErrorMessage string // OPPO 返回的错误信息
+	Err          error  // 校验失败、请求失败或 ErrorCode 非0 时不为空
+}
+
+type batchResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    []struct {
+		MessageId      string `json:"messageId"`
+		RegistrationId string `json:"registrationId"`
+		ErrorCode      int    `json:"errorCode"`
+		ErrorMessage   string `json:"errorMessage"`
+	} `json:"data"`
+}
+
+// NotifyBatch 通过 unicast_batch 为每个目标发送各自的消息，每1000条为一个请求；
+// 未通过校验的消息不会发送，其错误记录在对应的结果中
+func (o *client) NotifyBatch(ctx context.Context, reqs []*MessageRequest) ([]BatchResult, error) {
+	if len(reqs) == 0 {
+		return nil, errors.New("batch messages is empty")
+	}
+	results := make([]BatchResult, len(reqs))
+	pending := make([]int, 0, len(reqs))
+	withDefaults := make([]*MessageRequest, len(reqs))
+	for i, req := range reqs {
+		results[i].TargetValue = req.TargetValue
+		r := *req
+		o.applyDefaults(&r.Notification)
+		withDefaults[i] = &r
+		if err := r.Validate(); err != nil {
+			results[i].Err = err
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	for start := 0; start < len(pending); start += maxBatchMessages {
+		end := start + maxBatchMessages
+		if end > len(pending) {
+			end = len(pending)
+		}
+		o.sendBatch(ctx, withDefaults, pending[start:end], results)
+	}
+	return results, nil
+}
+
+func (o *client) sendBatch(ctx context.Context, reqs []*MessageRequest, indexes []int, results []BatchResult) {
+	fail := func(err error) {
+		for _, i := range indexes {
+			results[i].Err = err
+		}
+	}
+
+	messages := make([]*MessageRequest, len(indexes))
+	for n, i := range indexes {
+		messages[n] = reqs[i]
+	}
+	data, err := json.Marshal(messages)
+	if err != nil {
+		fail(err)
+		return
+	}
+	token, err := o.authToken(ctx)
+	if err != nil {
+		fail(err)
+		return
+	}
+	form := url.Values{}
+	form.Add("messages", string(data))
+
+	var r batchResponse
+	if err = o.post(ctx, token, o.op.BatchURL, []byte(form.Encode()), &r); err != nil {
+		fail(err)
+		return
+	}
+	if r.Code != 0 {
+		fail(errors.New(fmt.Sprintf("request failed code %d %s", r.Code, r.Message)))
+		return
+	}
+
+	// OPPO 按 registrationId 返回结果，同一目标出现多次时按出现顺序对应
+	byTarget := map[string][]int{}
+	for _, i := range indexes {
+		byTarget[reqs[i].TargetValue] = append(byTarget[reqs[i].TargetValue], i)
+	}
+	for _, d := range r.Data {
+		queue := byTarget[d.RegistrationId]
+		if len(queue) == 0 {
+			continue
+		}
+		i := queue[0]
+		byTarget[d.RegistrationId] = queue[1:]
+
+		results[i].MessageId = d.MessageId
+		results[i].ErrorCode = d.ErrorCode
+		results[i].ErrorMessage = d.ErrorMessage
+		if d.ErrorCode != 0 {
+			results[i].Err = errors.New(fmt.Sprintf("notify failed code %d %s", d.ErrorCode, d.ErrorMessage))
+		}
+	}
+	for _, queue := range byTarget {
+		for _, i := range queue {
+			results[i].Err = errors.New("no result returned for target")
+		}
+	}
+}
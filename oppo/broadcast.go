@@ -0,0 +1,159 @@
+package oppo
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	saveMessageContentPath = "/server/v1/message/notification/save_message_content"
+
+	maxBroadcastRegIds = 1000
+	regIdSeparator     = ";"
+)
+
+// BroadcastRequest 将已保存的消息内容推送给一组 registration_id、别名、标签表达式或全部用户
+type BroadcastRequest struct {
+	MessageId   string   // SaveMessageContent 返回的 message_id
+	TargetType  int16    // TargetTypeAll、TargetTypeRegId、TargetTypeAlias 或 TargetTypeTag
+	TargetValue string   // 标签表达式，TargetTypeTag 时使用
+	Targets     []string // registration_id 或别名列表，最多1000个
+}
+
+// SaveMessageContent 保存通知内容，返回用于广播的 message_id
+func (o *client) SaveMessageContent(ctx context.Context, n *Notification) (string, error) {
+	withDefaults := *n
+	o.applyDefaults(&withDefaults)
+	var e ValidationError
+	withDefaults.validate(&e)
+	if err := e.orNil(); err != nil {
+		return "", err
+	}
+	form, err := notificationValues(&withDefaults)
+	if err != nil {
+		return "", err
+	}
+	token, err := o.authToken(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var r Response
+	if err = o.post(ctx, token, o.endpoint(saveMessageContentPath), []byte(form.Encode()), &r); err != nil {
+		return "", err
+	}
+	if err = r.err(); err != nil {
+		return "", err
+	}
+	if r.Data == nil || r.Data.BroadcastMessageId == "" {
+		return "", errors.New("save message content returned no message id")
+	}
+	return r.Data.BroadcastMessageId, nil
+}
+
+// Broadcast 推送已保存的消息，成功时 Response.Data.TaskId 为本次广播的任务ID
+func (o *client) Broadcast(ctx context.Context, req *BroadcastRequest) (*Response, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Add("message_id", req.MessageId)
+	form.Add("target_type", strconv.Itoa(int(req.TargetType)))
+	if value := req.targetValue(); value != "" {
+		form.Add("target_value", value)
+	}
+	token, err := o.authToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var r Response
+	if err = o.post(ctx, token, o.op.BroadcastURL, []byte(form.Encode()), &r); err != nil {
+		return nil, err
+	}
+	if err = r.err(); err != nil {
+		return &r, err
+	}
+	return &r, nil
+}
+
+// NotifyBroadcast 先保存通知内容再广播，即 SaveMessageContent 与 Broadcast 的组合
+func (o *client) NotifyBroadcast(ctx context.Context, n *Notification, targetType int16, targetValue string, targets []string) (*Response, error) {
+	messageId, err := o.SaveMessageContent(ctx, n)
+	if err != nil {
+		return nil, err
+	}
+	return o.Broadcast(ctx, &BroadcastRequest{
+		MessageId:   messageId,
+		TargetType:  targetType,
+		TargetValue: targetValue,
+		Targets:     targets,
+	})
+}
+
+func (r *BroadcastRequest) validate() error {
+	if r.MessageId == "" {
+		return errors.New("broadcast message id is empty")
+	}
+	switch r.TargetType {
+	case TargetTypeAll:
+		if r.TargetValue != "" || len(r.Targets) != 0 {
+			return errors.New("broadcast to all users must not have target value")
+		}
+	case TargetTypeRegId, TargetTypeAlias:
+		if len(r.Targets) == 0 {
+			return errors.New("broadcast targets is empty")
+		}
+		if len(r.Targets) > maxBroadcastRegIds {
+			return errors.New("too many broadcast targets, at most 1000")
+		}
+	case TargetTypeTag:
+		if r.TargetValue == "" {
+			return errors.New("broadcast tag expression is empty")
+		}
+	default:
+		return errors.New(fmt.Sprintf("unsupported broadcast target type %d", r.TargetType))
+	}
+	return nil
+}
+
+func (r *BroadcastRequest) targetValue() string {
+	if len(r.Targets) > 0 {
+		return strings.Join(r.Targets, regIdSeparator)
+	}
+	return r.TargetValue
+}
+
+func (m *Response) err() error {
+	if m.Code == 0 {
+		return nil
+	}
+	return errors.New(fmt.Sprintf("request failed code %d %s", m.Code, m.Message))
+}
+
+// notificationValues 将通知按 json 标签展开为表单字段，空字符串字段不提交
+func notificationValues(n *Notification) (url.Values, error) {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return nil, err
+	}
+	fields := map[string]interface{}{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&fields); err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	for k, v := range fields {
+		if s := fmt.Sprint(v); s != "" {
+			form.Add(k, s)
+		}
+	}
+	return form, nil
+}
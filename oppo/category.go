@@ -0,0 +1,79 @@
+package oppo
+
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	// 私信通道：通讯与服务类消息
+	CategoryIM             = "IM"              // 即时聊天、音频、视频通话
+	CategoryAccount        = "ACCOUNT"         // 账号及资产变化
+	CategoryDeviceReminder = "DEVICE_REMINDER" // 设备信息
+	CategoryOrder          = "ORDER"           // 订单及物流
+	CategoryTodo           = "TODO"            // 日程、待办
+	CategorySubscription   = "SUBSCRIPTION"    // 个人订阅
+
+	// 公信通道：内容与营销类消息
+	CategoryNews      = "NEWS"      // 新闻资讯
+	CategoryContent   = "CONTENT"   // 内容推荐
+	CategoryMarketing = "MARKETING" // 运营活动
+	CategorySocial    = "SOCIAL"    // 社交动态
+
+	NotifyLevelBar      = 1  // 通知栏
+	NotifyLevelLock     = 2  // 通知栏+锁屏
+	NotifyLevelStrength = 16 // 通知栏+锁屏+横幅+震动+铃声，仅私信通道可用
+)
+
+// categories 为 OPPO 支持的消息分类，value 表示是否属于私信通道（允许强提醒）
+var categories = map[string]bool{
+	CategoryIM:             true,
+	CategoryAccount:        true,
+	CategoryDeviceReminder: true,
+	CategoryOrder:          true,
+	CategoryTodo:           true,
+	CategorySubscription:   true,
+	CategoryNews:           false,
+	CategoryContent:        false,
+	CategoryMarketing:      false,
+	CategorySocial:         false,
+}
+
+// validateCategory 检查消息分类与提醒等级的组合是否被 OPPO 允许
+func (n *Notification) validateCategory() error {
+	return checkCategory(n.Category, n.NotifyLevel)
+}
+
+func checkCategory(category string, level int) error {
+	private, ok := categories[category]
+	if category != "" && !ok {
+		return errors.New(fmt.Sprintf("unknown category %s", category))
+	}
+	switch level {
+	case 0:
+		return nil
+	case NotifyLevelBar, NotifyLevelLock:
+	case NotifyLevelStrength:
+		if category != "" && !private {
+			return errors.New(fmt.Sprintf("notify level 16 is not allowed for category %s", category))
+		}
+	default:
+		return errors.New(fmt.Sprintf("wrong notify level %d", level))
+	}
+	if category == "" {
+		return errors.New("notify level requires category")
+	}
+	return nil
+}
+
+// applyDefaults 为通知填充客户端配置的默认值；分类与提醒等级作为一组处理，
+// 仅在通知两者均未指定时填充，避免默认等级与通知自身的分类不匹配
+func (o *client) applyDefaults(n *Notification) {
+	if n.ChannelId == "" {
+		n.ChannelId = o.op.ChannelId
+	}
+	if n.Category == "" && n.NotifyLevel == 0 {
+		n.Category = o.op.Category
+		n.NotifyLevel = o.op.NotifyLevel
+	}
+}
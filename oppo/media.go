@@ -0,0 +1,121 @@
+package oppo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	smallPicturePath = "/server/v1/media/upload/small_picture"
+	bigPicturePath   = "/server/v1/media/upload/big_picture"
+
+	StyleStandard   = 1 // 标准样式
+	StyleBigText    = 2 // 长文本样式
+	StyleBigPicture = 3 // 大图样式
+
+	minPictureTTL     = time.Hour
+	maxPictureTTL     = 30 * 24 * time.Hour
+	defaultPictureTTL = 24 * time.Hour // 未指定 ttl 时 OPPO 保存图片的时长
+)
+
+// Picture 为上传到 OPPO 媒体服务器的图片
+type Picture struct {
+	Id        string    // 通知中引用的图片ID
+	ExpiresAt time.Time // 图片过期时间，过期后引用该图片的通知将发送失败
+}
+
+type uploadResponse struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    struct {
+		SmallPictureId string `json:"small_picture_id"`
+		BigPictureId   string `json:"big_picture_id"`
+	} `json:"data"`
+}
+
+// UploadSmallPicture 上传通知图标，ttl 为0时使用 OPPO 默认的1天，允许1小时至30天
+func (o *client) UploadSmallPicture(ctx context.Context, filename string, image []byte, ttl time.Duration) (*Picture, error) {
+	r, expiresAt, err := o.upload(ctx, smallPicturePath, filename, image, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return &Picture{Id: r.Data.SmallPictureId, ExpiresAt: expiresAt}, nil
+}
+
+// UploadBigPicture 上传大图样式的图片，ttl 为0时使用 OPPO 默认的1天，允许1小时至30天
+func (o *client) UploadBigPicture(ctx context.Context, filename string, image []byte, ttl time.Duration) (*Picture, error) {
+	r, expiresAt, err := o.upload(ctx, bigPicturePath, filename, image, ttl)
+	if err != nil {
+		return nil, err
+	}
+	return &Picture{Id: r.Data.BigPictureId, ExpiresAt: expiresAt}, nil
+}
+
+func (o *client) upload(ctx context.Context, path, filename string, image []byte, ttl time.Duration) (*uploadResponse, time.Time, error) {
+	if len(image) == 0 {
+		return nil, time.Time{}, errors.New("picture is empty")
+	}
+	if ttl != 0 && (ttl < minPictureTTL || ttl > maxPictureTTL) {
+		return nil, time.Time{}, errors.New("picture ttl must be between 1 hour and 30 days")
+	}
+
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	if ttl != 0 {
+		if err := w.WriteField("picture_ttl", strconv.FormatInt(int64(ttl/time.Second), 10)); err != nil {
+			return nil, time.Time{}, err
+		}
+	}
+	part, err := w.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if _, err = part.Write(image); err != nil {
+		return nil, time.Time{}, err
+	}
+	if err = w.Close(); err != nil {
+		return nil, time.Time{}, err
+	}
+
+	token, err := o.authToken(ctx)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	if ttl == 0 {
+		ttl = defaultPictureTTL
+	}
+	expiresAt := time.Now().Add(ttl)
+
+	var r uploadResponse
+	api := strings.TrimSuffix(o.op.MediaHost, "/") + path
+	if err = o.postContent(ctx, token, api, w.FormDataContentType(), body.Bytes(), &r); err != nil {
+		return nil, time.Time{}, err
+	}
+	if r.Code != 0 {
+		return nil, time.Time{}, errors.New(fmt.Sprintf("upload failed code %d %s", r.Code, r.Message))
+	}
+	return &r, expiresAt, nil
+}
+
+// validateStyle 检查通知样式与引用的图片是否一致
+func (n *Notification) validateStyle() error {
+	switch n.Style {
+	case 0, StyleStandard, StyleBigText:
+		if n.BigPictureId != "" {
+			return errors.New("big picture id requires style 3")
+		}
+	case StyleBigPicture:
+		if n.BigPictureId == "" {
+			return errors.New("style 3 requires big picture id")
+		}
+	default:
+		return errors.New(fmt.Sprintf("wrong notification style %d", n.Style))
+	}
+	return nil
+}
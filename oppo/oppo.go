@@ -14,6 +14,22 @@ import (
 	"time"
 )
 
+const (
+	DefaultHost         = "https://api.push.oppomobile.com"
+	DefaultPushURL      = DefaultHost + "/server/v1/message/notification/unicast"
+	DefaultAuthURL      = DefaultHost + "/server/v1/auth"
+	DefaultBatchURL     = DefaultHost + "/server/v1/message/notification/unicast_batch"
+	DefaultBroadcastURL = DefaultHost + "/server/v1/message/notification/broadcast"
+	DefaultMediaHost    = "https://api-media.push.oppomobile.com"
+)
+
+const (
+	TargetTypeAll   int16 = 1 // 全部用户，仅用于广播
+	TargetTypeRegId int16 = 2 // registration_id
+	TargetTypeAlias int16 = 5 // 别名
+	TargetTypeTag   int16 = 6 // 标签表达式，仅用于广播
+)
+
 type MessageRequest struct {
 	TargetType   int16        `json:"target_type"`
 	TargetValue  string       `json:"target_value"`
@@ -21,15 +37,22 @@ type MessageRequest struct {
 }
 
 type Notification struct {
-	Title             string `json:"title"`
-	SubTitle          string `json:"sub_title"`
-	Content           string `json:"content"`
-	Expire            int64  `json:"off_line_ttl"`
-	ActionParams      string `json:"action_parameters"`
-	ClickActionType   int    `json:"click_action_type"`
-	ClickActionUrl    string `json:"click_action_url"`
-	CallBackUrl       string `json:"call_back_url"`
-	CallBackParameter string `json:"call_back_parameter"`
+	Title               string `json:"title"`
+	SubTitle            string `json:"sub_title"`
+	Content             string `json:"content"`
+	Expire              int64  `json:"off_line_ttl"`
+	ActionParams        string `json:"action_parameters"`
+	ClickActionType     int    `json:"click_action_type"`
+	ClickActionUrl      string `json:"click_action_url"`
+	ClickActionActivity string `json:"click_action_activity,omitempty"` // 打开应用内页时的 activity，ClickActionType 为 1 或 4 时必填
+	CallBackUrl         string `json:"call_back_url"`
+	CallBackParameter   string `json:"call_back_parameter"`
+	ChannelId           string `json:"channel_id,omitempty"`       // 通知渠道ID
+	Category            string `json:"category,omitempty"`         // 消息分类，见 Category* 常量
+	NotifyLevel         int    `json:"notify_level,omitempty"`     // 提醒等级，见 NotifyLevel* 常量，要求设置 Category
+	Style               int    `json:"style,omitempty"`            // 通知样式，1 标准，2 长文本，3 大图
+	SmallPictureId      string `json:"small_picture_id,omitempty"` // UploadSmallPicture 返回的图标ID
+	BigPictureId        string `json:"big_picture_id,omitempty"`   // UploadBigPicture 返回的大图ID，要求 Style 为 3
 }
 
 type Response struct {
@@ -77,6 +100,10 @@ func NewOppoClient(op sdk.Oppo) (*client, error) {
 	if op.MasterSecret == "" {
 		return nil, errors.New("master secret empty")
 	}
+	if err := checkCategory(op.Category, op.NotifyLevel); err != nil {
+		return nil, err
+	}
+	SetDefaults(&op)
 
 	return &client{
 		httpclient: http.NewHTTPClient(),
@@ -89,48 +116,95 @@ func NewOppoClient(op sdk.Oppo) (*client, error) {
 	}, nil
 }
 
+// SetDefaults 为未填写的接口地址填充官方默认地址
+func SetDefaults(op *sdk.Oppo) {
+	if op.PushURL == "" {
+		op.PushURL = DefaultPushURL
+	}
+	if op.AuthURL == "" {
+		op.AuthURL = DefaultAuthURL
+	}
+	if op.BatchURL == "" {
+		op.BatchURL = DefaultBatchURL
+	}
+	if op.BroadcastURL == "" {
+		op.BroadcastURL = DefaultBroadcastURL
+	}
+	if op.MediaHost == "" {
+		op.MediaHost = DefaultMediaHost
+	}
+}
+
 func (o *client) Notify(ctx context.Context, req sdk.MessageRequest) (sdk.MessageResponse, error) {
+	if r, ok := req.(*MessageRequest); ok {
+		withDefaults := *r
+		o.applyDefaults(&withDefaults.Notification)
+		req = &withDefaults
+	}
 	err := req.Validate()
 	if err != nil {
 		return nil, err
 	}
 
-	token, err := o.authClient.GetAuthToken(ctx)
+	token, err := o.authToken(ctx)
 	if err != nil {
 		return nil, err
 	}
-	if token == "" {
-		return nil, errors.New("can not get token")
-	}
 
 	data, err := req.GetRequestBody()
 	if err != nil {
 		return nil, err
 	}
+	var r Response
+	if err = o.post(ctx, token, o.op.PushURL, data, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// post 携带 auth_token 以表单提交请求并解析 JSON 响应
+func (o *client) post(ctx context.Context, token, api string, data []byte, out interface{}) error {
+	return o.postContent(ctx, token, api, "application/x-www-form-urlencoded", data, out)
+}
+
+func (o *client) postContent(ctx context.Context, token, api, contentType string, data []byte, out interface{}) error {
 	resp, err := o.httpclient.Do(ctx, &http.PushRequest{
 		Method: "POST",
-		URL:    o.op.PushURL,
+		URL:    api,
 		Body:   data,
 		Header: []http.HTTPOption{
-			http.SetHeader("Content-Type", "application/x-www-form-urlencoded"),
+			http.SetHeader("Content-Type", contentType),
 			http.SetHeader("auth_token", token),
 		},
 	})
-
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if resp.Status != 200 {
-		return nil, errors.New(fmt.Sprintf("notify request failed %s", string(resp.Body)))
+		return errors.New(fmt.Sprintf("notify request failed %s", string(resp.Body)))
 	}
+	return json.Unmarshal(resp.Body, out)
+}
 
-	var r Response
-	err = json.Unmarshal(resp.Body, &r)
+// authToken 获取鉴权 token，获取失败或为空时返回错误
+func (o *client) authToken(ctx context.Context) (string, error) {
+	token, err := o.authClient.GetAuthToken(ctx)
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	if token == "" {
+		return "", errors.New("can not get token")
 	}
+	return token, nil
+}
 
-	return &r, nil
+// endpoint 以 PushURL 的主机拼接接口路径
+func (o *client) endpoint(path string) string {
+	u, err := url.Parse(o.op.PushURL)
+	if err != nil || u.Host == "" {
+		return DefaultHost + path
+	}
+	return u.Scheme + "://" + u.Host + path
 }
 
 func (m *Response) GetResult() string {
@@ -141,10 +215,6 @@ func (m *Response) GetData() map[string]string {
 	return nil
 }
 
-func (r *MessageRequest) Validate() error {
-	return nil
-}
-
 func (r *MessageRequest) GetRequestBody() ([]byte, error) {
 	data, err := json.Marshal(r)
 	if err != nil {
@@ -213,3 +283,27 @@ func (t *TokenInfo) GetAccessToken() string {
 func (t *TokenInfo) IsValidate() bool {
 	return time.Now().Sub(t.CreateTime).Hours() < 24.0
 }
+
+// FromMessage 将通用消息转换为发送到指定 registration_id 的 OPPO 单推消息
+func FromMessage(msg *sdk.Message, regId string) (*MessageRequest, error) {
+	req := &MessageRequest{
+		TargetType:  TargetTypeRegId,
+		TargetValue: regId,
+		Notification: Notification{
+			Title:             msg.Title,
+			SubTitle:          msg.SubTitle,
+			Content:           msg.Content,
+			ClickActionType:   0,
+			CallBackUrl:       msg.CallBack,
+			CallBackParameter: msg.CallbackParam,
+		},
+	}
+	if len(msg.Extra) > 0 {
+		params, err := json.Marshal(msg.Extra)
+		if err != nil {
+			return nil, err
+		}
+		req.Notification.ActionParams = string(params)
+	}
+	return req, nil
+}
@@ -2,9 +2,16 @@ package oppo
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	sdk "github.com/holicc/push-sdk"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestOPPONotify(t *testing.T) {
@@ -34,3 +41,304 @@ func TestOPPONotify(t *testing.T) {
 	}
 	fmt.Println(notify)
 }
+
+func TestFromMessage(t *testing.T) {
+	req, err := FromMessage(&sdk.Message{
+		Title:         "Hello",
+		SubTitle:      "Sub",
+		Content:       "Hello World",
+		Extra:         map[string]string{"orderId": "42"},
+		CallBack:      "https://example.com/callback",
+		CallbackParam: "biz-1",
+	}, "reg-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.TargetType != 2 || req.TargetValue != "reg-id" || req.Notification.SubTitle != "Sub" {
+		t.Errorf("unexpected request %v", req)
+	}
+	if req.Notification.ActionParams != `{"orderId":"42"}` || req.Notification.CallBackParameter != "biz-1" {
+		t.Errorf("unexpected notification %v", req.Notification)
+	}
+}
+
+func newTestServer(t *testing.T, mux *http.ServeMux) (*httptest.Server, *client) {
+	mux.HandleFunc("/server/v1/auth", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(fmt.Sprintf(`{"code":0,"data":{"auth_token":"token","create_time":%d}}`, time.Now().Unix())))
+	})
+	server := httptest.NewServer(mux)
+	opClient, err := NewOppoClient(sdk.Oppo{
+		Platform: sdk.Platform{
+			PushURL: server.URL + "/server/v1/message/notification/unicast",
+			AuthURL: server.URL + "/server/v1/auth",
+		},
+		BatchURL:     server.URL + "/server/v1/message/notification/unicast_batch",
+		BroadcastURL: server.URL + "/server/v1/message/notification/broadcast",
+		AppKey:       "key",
+		MasterSecret: "secret",
+	})
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	return server, opClient
+}
+
+func TestOPPOBroadcast(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(saveMessageContentPath, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		_, hasParams := r.PostForm["action_parameters"]
+		if r.Header.Get("auth_token") != "token" || r.PostForm.Get("title") != "Hello" ||
+			r.PostForm.Get("click_action_type") != "0" || hasParams {
+			t.Errorf("unexpected save request %v", r.PostForm)
+		}
+		w.Write([]byte(`{"code":0,"message":"Success","data":{"message_id":"msg-1"}}`))
+	})
+	mux.HandleFunc("/server/v1/message/notification/broadcast", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("message_id") != "msg-1" || r.PostForm.Get("target_type") != "2" ||
+			r.PostForm.Get("target_value") != "reg-1;reg-2" {
+			t.Errorf("unexpected broadcast request %v", r.PostForm)
+		}
+		w.Write([]byte(`{"code":0,"message":"Success","data":{"message_id":"msg-1","task_id":"task-1"}}`))
+	})
+	server, opClient := newTestServer(t, mux)
+	defer server.Close()
+
+	ctx := context.Background()
+	resp, err := opClient.NotifyBroadcast(ctx, &Notification{Title: "Hello", Content: "Hello World"},
+		TargetTypeRegId, "", []string{"reg-1", "reg-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data.TaskId != "task-1" || resp.Data.BroadcastMessageId != "msg-1" {
+		t.Errorf("unexpected response %+v", resp.Data)
+	}
+	if _, err := opClient.Broadcast(ctx, &BroadcastRequest{MessageId: "msg-1", TargetType: TargetTypeTag}); err == nil {
+		t.Error("expected error for empty tag expression")
+	}
+}
+
+func TestOPPONotifyBatch(t *testing.T) {
+	var requests int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/server/v1/message/notification/unicast_batch", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		r.ParseForm()
+		var messages []MessageRequest
+		if err := json.Unmarshal([]byte(r.PostForm.Get("messages")), &messages); err != nil {
+			t.Error(err)
+			return
+		}
+		data := make([]string, 0, len(messages))
+		for _, m := range messages {
+			if m.TargetValue == "reg-bad" {
+				data = append(data, `{"registrationId":"reg-bad","errorCode":10000,"errorMessage":"invalid registration id"}`)
+				continue
+			}
+			data = append(data, fmt.Sprintf(`{"registrationId":"%s","messageId":"msg-%s"}`, m.TargetValue, m.TargetValue))
+		}
+		w.Write([]byte(`{"code":0,"data":[` + strings.Join(data, ",") + `]}`))
+	})
+	server, opClient := newTestServer(t, mux)
+	defer server.Close()
+
+	reqs := make([]*MessageRequest, 0, 1001)
+	for i := 0; i < 1000; i++ {
+		reqs = append(reqs, &MessageRequest{
+			TargetType:   TargetTypeRegId,
+			TargetValue:  "reg-" + strconv.Itoa(i),
+			Notification: Notification{Title: "Hello", Content: "Hello " + strconv.Itoa(i)},
+		})
+	}
+	reqs = append(reqs, &MessageRequest{
+		TargetType:   TargetTypeRegId,
+		TargetValue:  "reg-bad",
+		Notification: Notification{Title: "Hello", Content: "Hello"},
+	})
+	results, err := opClient.NotifyBatch(context.Background(), reqs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requests != 2 || len(results) != len(reqs) {
+		t.Fatalf("unexpected %d requests and %d results", requests, len(results))
+	}
+	if results[42].Err != nil || results[42].MessageId != "msg-reg-42" {
+		t.Errorf("unexpected result %+v", results[42])
+	}
+	if results[1000].Err == nil || results[1000].ErrorCode != 10000 {
+		t.Errorf("unexpected result %+v", results[1000])
+	}
+}
+
+func TestOPPOCategory(t *testing.T) {
+	var message MessageRequest
+	mux := http.NewServeMux()
+	mux.HandleFunc("/server/v1/message/notification/unicast", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		message = MessageRequest{}
+		if err := json.Unmarshal([]byte(r.PostForm.Get("message")), &message); err != nil {
+			t.Error(err)
+		}
+		w.Write([]byte(`{"code":0,"data":{"messageId":"msg-1"}}`))
+	})
+	server, opClient := newTestServer(t, mux)
+	defer server.Close()
+	opClient.op.ChannelId = "orders"
+	opClient.op.Category = CategoryOrder
+	opClient.op.NotifyLevel = NotifyLevelStrength
+
+	req := &MessageRequest{
+		TargetType:   TargetTypeRegId,
+		TargetValue:  "reg-1",
+		Notification: Notification{Title: "Hello", Content: "Hello World"},
+	}
+	if _, err := opClient.Notify(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	n := message.Notification
+	if n.ChannelId != "orders" || n.Category != CategoryOrder || n.NotifyLevel != NotifyLevelStrength {
+		t.Errorf("unexpected notification %+v", n)
+	}
+	if req.Notification.Category != "" {
+		t.Errorf("request must not be modified %+v", req.Notification)
+	}
+
+	// 通知自行指定分类时不使用默认的提醒等级
+	req.Notification.Category = CategoryMarketing
+	if _, err := opClient.Notify(context.Background(), req); err != nil {
+		t.Fatal(err)
+	}
+	n = message.Notification
+	if n.Category != CategoryMarketing || n.NotifyLevel != 0 {
+		t.Errorf("unexpected notification %+v", n)
+	}
+
+	invalid := []Notification{
+		{Category: "GAME"},
+		{NotifyLevel: NotifyLevelLock},
+		{Category: CategoryMarketing, NotifyLevel: NotifyLevelStrength},
+		{Category: CategoryIM, NotifyLevel: 3},
+	}
+	for _, n := range invalid {
+		if err := n.validateCategory(); err == nil {
+			t.Errorf("expected error for %+v", n)
+		}
+	}
+	if _, err := NewOppoClient(sdk.Oppo{AppKey: "key", MasterSecret: "secret", Category: CategoryNews, NotifyLevel: NotifyLevelStrength}); err == nil {
+		t.Error("expected error for invalid default category")
+	}
+}
+
+func TestOPPOUploadPicture(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(bigPicturePath, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Error(err)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer file.Close()
+		if r.Header.Get("auth_token") != "token" || header.Filename != "banner.png" || r.FormValue("picture_ttl") != "7200" {
+			t.Errorf("unexpected upload %s %v", header.Filename, r.MultipartForm.Value)
+		}
+		w.Write([]byte(`{"code":0,"data":{"big_picture_id":"pic-1"}}`))
+	})
+	server, opClient := newTestServer(t, mux)
+	defer server.Close()
+	opClient.op.MediaHost = server.URL
+
+	picture, err := opClient.UploadBigPicture(context.Background(), "banner.png", []byte("png"), 2*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if picture.Id != "pic-1" || picture.ExpiresAt.Before(time.Now().Add(time.Hour)) {
+		t.Errorf("unexpected picture %+v", picture)
+	}
+	if _, err := opClient.UploadSmallPicture(context.Background(), "icon.png", []byte("png"), time.Minute); err == nil {
+		t.Error("expected error for short ttl")
+	}
+
+	n := Notification{Title: "Hello", Content: "Hello World", Style: StyleBigPicture}
+	if err := n.validateStyle(); err == nil {
+		t.Error("expected error for big picture style without picture")
+	}
+	n.BigPictureId = picture.Id
+	if err := n.validateStyle(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestOPPOValidate(t *testing.T) {
+	valid := &MessageRequest{
+		TargetType:  TargetTypeRegId,
+		TargetValue: "reg-1",
+		Notification: Notification{
+			Title:               "Hello",
+			Content:             "Hello World",
+			ClickActionType:     ClickActionActivity,
+			ClickActionActivity: "com.example.MainActivity",
+			ActionParams:        `{"orderId":"42"}`,
+			Expire:              3600,
+			CallBackUrl:         "https://example.com/callback",
+		},
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := &MessageRequest{
+		TargetType:  3,
+		TargetValue: "reg-1;reg-2",
+		Notification: Notification{
+			Title:           strings.Repeat("标", 51),
+			ClickActionType: ClickActionWeb,
+			ClickActionUrl:  "ftp://example.com",
+			ActionParams:    "orderId=42",
+			Expire:          -1,
+			CallBackUrl:     "example.com/" + strings.Repeat("a", 200),
+			Category:        "GAME",
+		},
+	}
+	err := invalid.Validate()
+	vErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("expected *ValidationError, got %v", err)
+	}
+	fields := map[string]bool{}
+	for _, v := range vErr.Violations {
+		fields[v.Field] = true
+	}
+	for _, field := range []string{
+		"target_type", "notification.title", "notification.content", "notification.click_action_url",
+		"notification.action_parameters", "notification.off_line_ttl", "notification.call_back_url",
+		"notification.category",
+	} {
+		if !fields[field] {
+			t.Errorf("missing violation for %s in %v", field, err)
+		}
+	}
+
+	invalid = &MessageRequest{
+		TargetType:   TargetTypeAlias,
+		TargetValue:  "alias-1",
+		Notification: Notification{Title: "Hello", Content: "Hello World", ClickActionType: 3},
+	}
+	if err := invalid.Validate(); err == nil || !strings.Contains(err.Error(), "notification.click_action_type") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestOPPODriverCheck(t *testing.T) {
+	if err := (driver{}).Check(&Response{Code: 0}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := (driver{}).Check(&Response{Code: 10000, Message: "Invalid RegistrationId"}); err == nil {
+		t.Error("expected error for rejected push")
+	}
+}
@@ -0,0 +1,138 @@
+package oppo
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ClickActionLaunch       = 0 // 启动应用
+	ClickActionIntentAction = 1 // 打开应用内页，ClickActionActivity 为 activity 的 intent action
+	ClickActionWeb          = 2 // 打开网页，ClickActionUrl 为 http(s) 地址
+	ClickActionActivity     = 4 // 打开应用内页，ClickActionActivity 为 activity 的完整类名
+	ClickActionIntentScheme = 5 // 打开 ClickActionUrl 指定的 intent scheme URL
+
+	maxTitleLength         = 50
+	maxSubTitleLength      = 10
+	maxContentLength       = 200
+	maxBigTextLength       = 1000
+	maxOfflineTTL          = 10 * 24 * 3600 // 离线消息最长保存10天，单位秒
+	maxCallBackUrlLength   = 200
+	maxCallBackParamLength = 100
+)
+
+// ValidationError 汇总消息中违反 OPPO 约束的全部问题，而不是在第一个问题处返回
+type ValidationError struct {
+	Violations []Violation
+}
+
+// Violation 为单个字段的问题，Field 为 OPPO 接口中的字段名
+type Violation struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		problems[i] = v.Field + ": " + v.Message
+	}
+	return "invalid oppo message: " + strings.Join(problems, "; ")
+}
+
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Violations = append(e.Violations, Violation{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+func (e *ValidationError) orNil() error {
+	if len(e.Violations) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate 检查单推消息是否满足 OPPO 的约束，存在问题时返回包含全部问题的 *ValidationError
+func (r *MessageRequest) Validate() error {
+	var e ValidationError
+	switch r.TargetType {
+	case TargetTypeRegId, TargetTypeAlias:
+		if r.TargetValue == "" {
+			e.add("target_value", "is empty")
+		} else if strings.ContainsAny(r.TargetValue, ";,") {
+			e.add("target_value", "must be a single target, use unicast_batch or broadcast for multiple targets")
+		}
+	default:
+		e.add("target_type", "must be 2 (registration_id) or 5 (alias), got %d", r.TargetType)
+	}
+	r.Notification.validate(&e)
+	return e.orNil()
+}
+
+func (n *Notification) validate(e *ValidationError) {
+	if n.Title == "" {
+		e.add("notification.title", "is empty")
+	} else if l := utf8.RuneCountInString(n.Title); l > maxTitleLength {
+		e.add("notification.title", "has %d characters, at most %d", l, maxTitleLength)
+	}
+	if l := utf8.RuneCountInString(n.SubTitle); l > maxSubTitleLength {
+		e.add("notification.sub_title", "has %d characters, at most %d", l, maxSubTitleLength)
+	}
+	contentLimit := maxContentLength
+	if n.Style == StyleBigText {
+		contentLimit = maxBigTextLength
+	}
+	if n.Content == "" {
+		e.add("notification.content", "is empty")
+	} else if l := utf8.RuneCountInString(n.Content); l > contentLimit {
+		e.add("notification.content", "has %d characters, at most %d", l, contentLimit)
+	}
+
+	switch n.ClickActionType {
+	case ClickActionLaunch:
+	case ClickActionIntentAction, ClickActionActivity:
+		if n.ClickActionActivity == "" {
+			e.add("notification.click_action_activity", "is required for click action type %d", n.ClickActionType)
+		}
+	case ClickActionWeb:
+		if u, err := url.Parse(n.ClickActionUrl); n.ClickActionUrl == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			e.add("notification.click_action_url", "must be a http or https url for click action type 2")
+		}
+	case ClickActionIntentScheme:
+		if n.ClickActionUrl == "" {
+			e.add("notification.click_action_url", "is required for click action type 5")
+		}
+	default:
+		e.add("notification.click_action_type", "must be 0, 1, 2, 4 or 5, got %d", n.ClickActionType)
+	}
+	if n.ActionParams != "" {
+		var params map[string]interface{}
+		if err := json.Unmarshal([]byte(n.ActionParams), &params); err != nil {
+			e.add("notification.action_parameters", "must be a json object")
+		}
+	}
+
+	if n.Expire < 0 || n.Expire > maxOfflineTTL {
+		e.add("notification.off_line_ttl", "must be between 0 and %d seconds, got %d", maxOfflineTTL, n.Expire)
+	}
+	if n.CallBackUrl != "" {
+		if l := len(n.CallBackUrl); l > maxCallBackUrlLength {
+			e.add("notification.call_back_url", "has %d bytes, at most %d", l, maxCallBackUrlLength)
+		}
+		if u, err := url.Parse(n.CallBackUrl); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			e.add("notification.call_back_url", "must be a http or https url")
+		}
+	}
+	if l := len(n.CallBackParameter); l > maxCallBackParamLength {
+		e.add("notification.call_back_parameter", "has %d bytes, at most %d", l, maxCallBackParamLength)
+	}
+
+	if err := n.validateCategory(); err != nil {
+		e.add("notification.category", "%s", err.Error())
+	}
+	if err := n.validateStyle(); err != nil {
+		e.add("notification.style", "%s", err.Error())
+	}
+}
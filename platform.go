@@ -17,10 +17,15 @@ type PushClient interface {
 }
 
 type PushConfig struct {
-	XiaoMi `json:"xiaomi"`
-	MeiZu  `json:"meizu"`
-	Oppo   `json:"oppo"`
-	Vivo   `json:"vivo"`
+	XiaoMi  `json:"xiaomi"`
+	MeiZu   `json:"meizu"`
+	Oppo    `json:"oppo"`
+	Vivo    `json:"vivo"`
+	Huawei  `json:"huawei"`
+	Honor   `json:"honor"`
+	APNs    `json:"apns"`
+	FCM     `json:"fcm"`
+	WebPush `json:"webpush"`
 }
 
 type Message struct {
@@ -33,39 +38,97 @@ type Message struct {
 	CallbackParam string            `json:"callbackParam"` // 自定义回执参数
 }
 
+// Extra 为小米通知栏消息预定义的 extra 字段，json 标签即发送时的表单键
 type Extra struct {
-	NotifyEffect string `json:"extra.notify_effect"` // 预定义通知栏消息的点击行为
-	IntentUri    string `json:"extra.intent_uri"`
+	NotifyEffect          string `json:"extra.notify_effect"`           // 预定义通知栏消息的点击行为，1 打开应用，2 打开应用内页面，3 打开网页
+	IntentUri             string `json:"extra.intent_uri"`              // 要打开的应用内页面，要求 NotifyEffect 为 2
+	WebUri                string `json:"extra.web_uri"`                 // 要打开的网页地址，要求 NotifyEffect 为 3
+	ChannelId             string `json:"extra.channel_id"`              // Android O 及以上的通知渠道ID
+	ChannelName           string `json:"extra.channel_name"`            // 通知渠道名称，与 ChannelId 同时设置
+	SoundUri              string `json:"extra.sound_uri"`               // 自定义铃声，格式为 android.resource://<包名>/raw/<文件名>
+	NotifyForeground      string `json:"extra.notify_foreground"`       // 应用在前台时是否弹出通知，0 不弹出，1 弹出
+	Ticker                string `json:"extra.ticker"`                  // 通知到达时状态栏显示的滚动文字
+	NotificationStyleType string `json:"extra.notification_style_type"` // 通知样式，1 大文本，2 大图
+	NotificationBigPicUri string `json:"extra.notification_bigPic_uri"` // 大图地址，要求 NotificationStyleType 为 2
 }
 
+// Platform 为各厂商推送与鉴权接口的完整地址，留空时由厂商包填充官方默认地址；
+// 以 URL 结尾的字段均为完整接口地址，以 Host 结尾的字段为主机地址，接口路径由厂商包拼接
 type Platform struct {
-	PushURL string
-	AuthURL string
+	PushURL string `json:"pushURL"` // 单推接口
+	AuthURL string `json:"authURL"` // 鉴权接口
 }
 
 type XiaoMi struct {
 	Platform
-	AppPkgName string `json:"appPkgName"`
-	AppSecret  string `json:"appSecret"`
+	BroadcastURL string `json:"broadcastURL"` // 全量推送接口，为空时以 PushURL 的主机拼接
+	StatsURL     string `json:"statsURL"`     // 统计查询接口，为空时以 PushURL 的主机拼接
+	Region       string `json:"region"`       // 推送集群所在区域：china、global、europe、russia、india，为空时为 china
+	FeedbackHost string `json:"feedbackHost"` // 反馈服务的主机地址，用于拉取失效的 regId 与别名
+	AppPkgName   string `json:"appPkgName" required:"true"`
+	AppSecret    string `json:"appSecret" required:"true"`
 }
 
 type MeiZu struct {
+	Host       string `json:"host"` // Flyme 推送服务的主机地址
 	AppPkgName string `json:"appPkgName"`
-	AppId      string `json:"appId"`
-	AppSecret  string `json:"appSecret"`
+	AppId      string `json:"appId" required:"true"`
+	AppSecret  string `json:"appSecret" required:"true"`
 }
 
 type Oppo struct {
 	Platform
 	AppPkgName   string `json:"appPkgName"`
-	AppKey       string `json:"appKey"`
-	MasterSecret string `json:"masterSecret"`
+	AppKey       string `json:"appKey" required:"true"`
+	MasterSecret string `json:"masterSecret" required:"true"`
+	BatchURL     string `json:"batchURL"`     // 批量单推接口
+	BroadcastURL string `json:"broadcastURL"` // 广播推送接口
+	MediaHost    string `json:"mediaHost"`    // 媒体服务的主机地址，用于上传通知图片
+	ChannelId    string `json:"channelId"`    // 默认的通知渠道ID，消息未指定时使用
+	Category     string `json:"category"`     // 默认的消息分类，如 IM、ACCOUNT、MARKETING
+	NotifyLevel  int    `json:"notifyLevel"`  // 默认的提醒等级，1 通知栏，2 通知栏+锁屏，16 通知栏+锁屏+横幅+震动+铃声
 }
 
 type Vivo struct {
 	Platform
 	AppPkgName string `json:"appPkgName"`
-	AppId      string `json:"appId"`
-	AppKey     string `json:"appKey"`
-	AppSecret  string `json:"appSecret"`
+	AppId      string `json:"appId" required:"true"`
+	AppKey     string `json:"appKey" required:"true"`
+	AppSecret  string `json:"appSecret" required:"true"`
+}
+
+type Huawei struct {
+	Platform
+	AppPkgName string `json:"appPkgName"`
+	AppId      string `json:"appId" required:"true"`     // 同时作为 OAuth2 的 client_id
+	AppSecret  string `json:"appSecret" required:"true"` // OAuth2 的 client_secret
+}
+
+type Honor struct {
+	Platform
+	AppPkgName   string `json:"appPkgName"`
+	AppId        string `json:"appId" required:"true"`
+	ClientId     string `json:"clientId" required:"true"`
+	ClientSecret string `json:"clientSecret" required:"true"`
+}
+
+type APNs struct {
+	Host     string `json:"host"`                     // APNs 服务的主机地址，为空时根据 Sandbox 选择生产或开发环境
+	BundleId string `json:"bundleId" required:"true"` // 应用 Bundle ID，作为默认的 apns-topic
+	TeamId   string `json:"teamId" required:"true"`
+	KeyId    string `json:"keyId" required:"true"`   // .p8 私钥对应的 Key ID
+	AuthKey  string `json:"authKey" required:"true"` // .p8 私钥内容（PEM 格式）
+	Sandbox  bool   `json:"sandbox"`                 // 是否使用开发环境
+}
+
+type FCM struct {
+	Platform
+	ProjectId      string `json:"projectId"`                      // 为空时使用服务账号中的 project_id
+	ServiceAccount string `json:"serviceAccount" required:"true"` // 服务账号密钥 JSON 内容
+}
+
+type WebPush struct {
+	VAPIDPublicKey  string `json:"vapidPublicKey" required:"true"`  // base64url 编码的未压缩 P-256 公钥
+	VAPIDPrivateKey string `json:"vapidPrivateKey" required:"true"` // base64url 编码的 P-256 私钥
+	Subscriber      string `json:"subscriber" required:"true"`      // VAPID 联系方式，mailto: 或 https: 地址
 }
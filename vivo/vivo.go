@@ -3,6 +3,7 @@ package vivo
 import (
 	"context"
 	"crypto/md5"
+	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -14,6 +15,13 @@ import (
 	"time"
 )
 
+const (
+	DefaultPushURL = "https://api-push.vivo.com.cn/message/send"
+	DefaultAuthURL = "https://api-push.vivo.com.cn/message/auth"
+
+	maxRequestIdLength = 64
+)
+
 type MessageRequest struct {
 	RegId           string                 `json:"regId"`
 	Title           string                 `json:"title"`
@@ -81,6 +89,7 @@ func NewVivoClient(vi sdk.Vivo) (*client, error) {
 	if vi.AppSecret == "" {
 		return nil, errors.New("app secret empty")
 	}
+	SetDefaults(&vi)
 
 	return &client{
 		vi:     vi,
@@ -94,6 +103,17 @@ func NewVivoClient(vi sdk.Vivo) (*client, error) {
 	}, nil
 }
 
+// SetDefaults 为未填写的接口地址填充官方默认地址；客户端只调用单推与鉴权接口，
+// 其余 Platform 字段不会被读取
+func SetDefaults(vi *sdk.Vivo) {
+	if vi.PushURL == "" {
+		vi.PushURL = DefaultPushURL
+	}
+	if vi.AuthURL == "" {
+		vi.AuthURL = DefaultAuthURL
+	}
+}
+
 func (v *client) Notify(ctx context.Context, req sdk.MessageRequest) (sdk.MessageResponse, error) {
 	data, err := json.Marshal(req)
 	if err != nil {
@@ -149,6 +169,14 @@ func (v *MessageRequest) GetRequestBody() ([]byte, error) {
 	return data, nil
 }
 
+// err 判断 vivo 是否拒绝了推送，result 为0时表示成功
+func (v *MessageResponse) err() error {
+	if v.Result == 0 {
+		return nil
+	}
+	return errors.New(fmt.Sprintf("notify request failed result %d %s", v.Result, v.Desc))
+}
+
 func (v *MessageResponse) GetResult() string {
 	return strconv.Itoa(v.Result)
 }
@@ -217,3 +245,52 @@ func generateSign(appId, appKey, timestamp, sec string) string {
 	hash.Write([]byte(signStr))
 	return strings.ToLower(hex.EncodeToString(hash.Sum(nil)))
 }
+
+// FromMessage 将通用消息转换为发送到指定 regId 的 vivo 单推消息，点击打开应用；
+// requestId 由 BusinessId 与 regId 生成，见 newRequestId。vivo 通知没有副标题，因此 SubTitle 不被支持
+func FromMessage(msg *sdk.Message, regId string) (*MessageRequest, error) {
+	requestId, err := newRequestId(msg.BusinessId, regId)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &MessageRequest{
+		RegId:      regId,
+		Title:      msg.Title,
+		Content:    msg.Content,
+		SkipType:   1,
+		RequestId:  requestId,
+		NotifyType: 4,
+	}
+	if len(msg.Extra) > 0 {
+		req.ClientCustomMap = make(map[string]interface{}, len(msg.Extra))
+		for k, v := range msg.Extra {
+			req.ClientCustomMap[k] = v
+		}
+	}
+	if msg.CallBack != "" {
+		req.Extra = &SingleNotifyExtra{
+			CallBack:      msg.CallBack,
+			CallBackParam: msg.CallbackParam,
+		}
+	}
+	return req, nil
+}
+
+// newRequestId 生成每台设备唯一的 requestId：有 businessId 时由 businessId 与 regId 确定，
+// 同一消息重试时保持不变；超过 vivo 64 字符的限制时取二者的 MD5，否则使用随机值
+func newRequestId(businessId, regId string) (string, error) {
+	if businessId == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(b), nil
+	}
+	requestId := businessId + "-" + regId
+	if len(requestId) <= maxRequestIdLength {
+		return requestId, nil
+	}
+	sum := md5.Sum([]byte(requestId))
+	return hex.EncodeToString(sum[:]), nil
+}
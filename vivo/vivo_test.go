@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	sdk "github.com/holicc/push-sdk"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 )
@@ -38,3 +40,48 @@ func TestVivoNotify(t *testing.T) {
 	}
 	fmt.Println(notify)
 }
+
+func TestFromMessage(t *testing.T) {
+	req, err := FromMessage(&sdk.Message{
+		BusinessId: "biz-1",
+		Title:      "Hello",
+		Content:    "Hello World",
+		Extra:      map[string]string{"orderId": "42"},
+		CallBack:   "https://example.com/callback",
+	}, "reg-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if req.RequestId != "biz-1-reg-id" || req.ClientCustomMap["orderId"] != "42" || req.Extra.CallBack != "https://example.com/callback" {
+		t.Errorf("unexpected request %v", req)
+	}
+
+	// 同一消息发往多台设备时 requestId 各不相同
+	msg := &sdk.Message{Title: "Hello", Content: "Hello World"}
+	seen := map[string]bool{}
+	for _, businessId := range []string{"", strings.Repeat("b", 64)} {
+		msg.BusinessId = businessId
+		for i := 0; i < 100; i++ {
+			req, err := FromMessage(msg, "reg-"+strconv.Itoa(i))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if seen[req.RequestId] || len(req.RequestId) > 64 {
+				t.Fatalf("unexpected request id %s", req.RequestId)
+			}
+			seen[req.RequestId] = true
+		}
+	}
+}
+
+func TestVivoDriverCheck(t *testing.T) {
+	if err := (driver{}).Check(&MessageResponse{Result: 0}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if err := (driver{}).Check(&MessageResponse{Result: 10070, Desc: "regId invalid"}); err == nil {
+		t.Error("expected error for rejected push")
+	}
+}
@@ -0,0 +1,123 @@
+package webpush
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+)
+
+const (
+	recordSize   = 4096
+	saltSize     = 16
+	keySize      = 65
+	headerSize   = saltSize + 4 + 1 + keySize
+	gcmTagSize   = 16
+	maxPlaintext = recordSize - headerSize - gcmTagSize - 1
+)
+
+// encrypt 按 RFC 8291 使用 aes128gcm 内容编码加密消息，返回包含编码头的完整请求体，
+// 每次调用生成新的盐与临时密钥
+func encrypt(plaintext []byte, p256dh, auth string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	asPrivate, _, _, err := elliptic.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return encryptWith(plaintext, p256dh, auth, salt, asPrivate)
+}
+
+// encryptWith 使用给定的盐与应用服务器临时私钥加密，便于以 RFC 8291 附录 A 的测试向量校验
+func encryptWith(plaintext []byte, p256dh, auth string, salt, asPrivate []byte) ([]byte, error) {
+	if len(plaintext) > maxPlaintext {
+		return nil, errors.New("payload too large")
+	}
+	if len(salt) != saltSize {
+		return nil, errors.New("salt must be 16 bytes")
+	}
+
+	uaPublic, err := decodeBase64(p256dh)
+	if err != nil {
+		return nil, err
+	}
+	authSecret, err := decodeBase64(auth)
+	if err != nil {
+		return nil, err
+	}
+	if len(authSecret) != 16 {
+		return nil, errors.New("auth secret must be 16 bytes")
+	}
+
+	curve := elliptic.P256()
+	uaX, uaY := elliptic.Unmarshal(curve, uaPublic)
+	if uaX == nil {
+		return nil, errors.New("invalid p256dh public key")
+	}
+
+	asX, asY := curve.ScalarBaseMult(asPrivate)
+	asPublic := elliptic.Marshal(curve, asX, asY)
+
+	sx, _ := curve.ScalarMult(uaX, uaY, asPrivate)
+	ecdhSecret := make([]byte, 32)
+	sb := sx.Bytes()
+	copy(ecdhSecret[len(ecdhSecret)-len(sb):], sb)
+
+	// IKM = HKDF(auth_secret, ecdh_secret, "WebPush: info" || 0x00 || ua_public || as_public)
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublic...)
+	keyInfo = append(keyInfo, asPublic...)
+	ikm := hkdf(authSecret, ecdhSecret, keyInfo, 32)
+
+	cek := hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// 单条记录，以 0x02 作为最后一条记录的分隔符
+	record := append(append([]byte{}, plaintext...), 0x02)
+
+	body := make([]byte, headerSize, headerSize+len(record)+gcmTagSize)
+	copy(body, salt)
+	binary.BigEndian.PutUint32(body[saltSize:], recordSize)
+	body[saltSize+4] = keySize
+	copy(body[saltSize+5:], asPublic)
+
+	return gcm.Seal(body, nonce, record, nil), nil
+}
+
+// hkdf 为 RFC 5869 的 HKDF-SHA256，输出长度不超过32字节
+func hkdf(salt, ikm, info []byte, length int) []byte {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write(ikm)
+	prk := mac.Sum(nil)
+
+	mac = hmac.New(sha256.New, prk)
+	mac.Write(info)
+	mac.Write([]byte{0x01})
+	return mac.Sum(nil)[:length]
+}
+
+// decodeBase64 兼容订阅信息中常见的 base64url 与标准 base64 编码，有无填充均可
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(s, "=")
+	if strings.ContainsAny(s, "+/") {
+		return base64.RawStdEncoding.DecodeString(s)
+	}
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,249 @@
+package webpush
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"encoding/json"
+	"errors"
+	"fmt"
+	sdk "github.com/holicc/push-sdk"
+	"github.com/holicc/push-sdk/http"
+	"github.com/holicc/push-sdk/jwt"
+	"math/big"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	vapidExpiration = 12 * time.Hour
+	defaultTTL      = 4 * 7 * 24 * 60 * 60
+)
+
+var topicPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,32}$`)
+
+// Subscription 为浏览器 PushManager.subscribe 返回的订阅信息
+type Subscription struct {
+	Endpoint string `json:"endpoint"`
+	Keys     Keys   `json:"keys"`
+}
+
+type Keys struct {
+	P256dh string `json:"p256dh"`
+	Auth   string `json:"auth"`
+}
+
+type MessageRequest struct {
+	Subscription Subscription
+	Payload      []byte // 消息内容，为空时发送不带内容的推送
+	TTL          int    // 推送服务保留消息的时长，单位秒，0 表示使用默认的4周
+	Urgency      string // very-low、low、normal 或 high
+	Topic        string // 相同 Topic 的未送达消息会被替换，不超过32个 base64url 字符
+}
+
+type MessageResponse struct {
+	Status   int
+	Location string // 推送服务创建的消息资源地址
+}
+
+type client struct {
+	wp     sdk.WebPush
+	key    *ecdsa.PrivateKey
+	client *http.HTTPClient
+}
+
+func NewWebPushClient(wp sdk.WebPush) (*client, error) {
+	if wp.VAPIDPrivateKey == "" {
+		return nil, errors.New("vapid private key empty")
+	}
+	if wp.VAPIDPublicKey == "" {
+		return nil, errors.New("vapid public key empty")
+	}
+	if wp.Subscriber == "" {
+		return nil, errors.New("vapid subscriber empty")
+	}
+	key, err := parseVAPIDKey(wp.VAPIDPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &client{
+		wp:     wp,
+		key:    key,
+		client: http.NewSecureHTTPClient(),
+	}, nil
+}
+
+func (c *client) Notify(ctx context.Context, req sdk.MessageRequest) (sdk.MessageResponse, error) {
+	if e := req.Validate(); e != nil {
+		return nil, e
+	}
+	r, ok := req.(*MessageRequest)
+	if !ok {
+		return nil, errors.New("unsupported message request type")
+	}
+
+	token, err := c.vapidToken(r.Subscription.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := r.TTL
+	if ttl == 0 {
+		ttl = defaultTTL
+	}
+	header := []http.HTTPOption{
+		http.SetHeader("TTL", strconv.Itoa(ttl)),
+		http.SetHeader("Authorization", fmt.Sprintf("vapid t=%s, k=%s", token, strings.TrimRight(c.wp.VAPIDPublicKey, "="))),
+	}
+	if r.Urgency != "" {
+		header = append(header, http.SetHeader("Urgency", r.Urgency))
+	}
+	if r.Topic != "" {
+		header = append(header, http.SetHeader("Topic", r.Topic))
+	}
+
+	var body []byte
+	if len(r.Payload) > 0 {
+		body, err = r.GetRequestBody()
+		if err != nil {
+			return nil, err
+		}
+		header = append(header,
+			http.SetHeader("Content-Encoding", "aes128gcm"),
+			http.SetHeader("Content-Type", "application/octet-stream"),
+		)
+	}
+
+	resp, err := c.client.Do(ctx, &http.PushRequest{
+		Method: "POST",
+		URL:    r.Subscription.Endpoint,
+		Body:   body,
+		Header: header,
+	})
+	if err != nil {
+		return nil, err
+	}
+	mr := MessageResponse{
+		Status:   resp.Status,
+		Location: resp.Header.Get("Location"),
+	}
+	if resp.Status < 200 || resp.Status > 299 {
+		// 失败时同时返回响应，调用方可根据 Expired 清理失效的订阅
+		return &mr, errors.New(fmt.Sprintf("notify request failed %d %s", resp.Status, string(resp.Body)))
+	}
+
+	return &mr, nil
+}
+
+// vapidToken 按 RFC 8292 签发 VAPID JWT，aud 为推送服务的源
+func (c *client) vapidToken(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	return jwt.SignES256(c.key, map[string]interface{}{
+		"typ": "JWT",
+	}, map[string]interface{}{
+		"aud": u.Scheme + "://" + u.Host,
+		"exp": time.Now().Add(vapidExpiration).Unix(),
+		"sub": c.wp.Subscriber,
+	})
+}
+
+func (m *MessageRequest) Validate() error {
+	u, err := url.Parse(m.Subscription.Endpoint)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return errors.New("subscription endpoint invalid")
+	}
+	if len(m.Payload) > 0 && (m.Subscription.Keys.P256dh == "" || m.Subscription.Keys.Auth == "") {
+		return errors.New("subscription keys empty")
+	}
+	if len(m.Payload) > maxPlaintext {
+		return errors.New(fmt.Sprintf("payload longer than %d bytes", maxPlaintext))
+	}
+	if m.TTL < 0 {
+		return errors.New("ttl must not be negative")
+	}
+	switch m.Urgency {
+	case "", "very-low", "low", "normal", "high":
+	default:
+		return errors.New("wrong urgency")
+	}
+	if m.Topic != "" && !topicPattern.MatchString(m.Topic) {
+		return errors.New("topic must be at most 32 base64url characters")
+	}
+	return nil
+}
+
+// GetRequestBody 返回 aes128gcm 加密后的消息体，每次调用使用新的临时密钥与盐
+func (m *MessageRequest) GetRequestBody() ([]byte, error) {
+	return encrypt(m.Payload, m.Subscription.Keys.P256dh, m.Subscription.Keys.Auth)
+}
+
+func (m *MessageResponse) err() error {
+	if m.Status >= 200 && m.Status <= 299 {
+		return nil
+	}
+	return errors.New(fmt.Sprintf("notify request failed %d", m.Status))
+}
+
+func (m *MessageResponse) GetResult() string {
+	return strconv.Itoa(m.Status)
+}
+
+// Expired 判断订阅是否已失效（404 或 410），失效的订阅应从存储中删除
+func (m *MessageResponse) Expired() bool {
+	return m.Status == 404 || m.Status == 410
+}
+
+func (m *MessageResponse) GetData() map[string]string {
+	return map[string]string{
+		"location": m.Location,
+	}
+}
+
+func parseVAPIDKey(privateKey string) (*ecdsa.PrivateKey, error) {
+	d, err := decodeBase64(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(d) != 32 {
+		return nil, errors.New("vapid private key must be 32 bytes")
+	}
+
+	curve := elliptic.P256()
+	key := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(d)}
+	key.PublicKey.Curve = curve
+	key.PublicKey.X, key.PublicKey.Y = curve.ScalarBaseMult(d)
+	return key, nil
+}
+
+// FromMessage 将通用消息编码为 JSON 后发送到指定订阅，subscription 为
+// PushSubscription.toJSON() 的结果
+func FromMessage(msg *sdk.Message, subscription string) (*MessageRequest, error) {
+	var sub Subscription
+	if err := json.Unmarshal([]byte(subscription), &sub); err != nil {
+		return nil, err
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &MessageRequest{
+		Subscription: sub,
+		Payload:      payload,
+		Topic:        topicFromBusinessId(msg.BusinessId),
+	}, nil
+}
+
+func topicFromBusinessId(businessId string) string {
+	if topicPattern.MatchString(businessId) {
+		return businessId
+	}
+	return ""
+}
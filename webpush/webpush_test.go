@@ -0,0 +1,205 @@
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	sdk "github.com/holicc/push-sdk"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func decrypt(t *testing.T, body, uaPrivate, uaPublic, authSecret []byte) []byte {
+	salt := body[:saltSize]
+	if rs := binary.BigEndian.Uint32(body[saltSize:]); rs != recordSize {
+		t.Fatalf("unexpected record size %d", rs)
+	}
+	idLen := int(body[saltSize+4])
+	asPublic := body[saltSize+5 : saltSize+5+idLen]
+
+	curve := elliptic.P256()
+	asX, asY := elliptic.Unmarshal(curve, asPublic)
+	sx, _ := curve.ScalarMult(asX, asY, uaPrivate)
+	ecdhSecret := make([]byte, 32)
+	sb := sx.Bytes()
+	copy(ecdhSecret[32-len(sb):], sb)
+
+	keyInfo := append([]byte("WebPush: info\x00"), uaPublic...)
+	keyInfo = append(keyInfo, asPublic...)
+	ikm := hkdf(authSecret, ecdhSecret, keyInfo, 32)
+	cek := hkdf(salt, ikm, []byte("Content-Encoding: aes128gcm\x00"), 16)
+	nonce := hkdf(salt, ikm, []byte("Content-Encoding: nonce\x00"), 12)
+
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	plain, err := gcm.Open(nil, nonce, body[saltSize+5+idLen:], nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain[len(plain)-1] != 0x02 {
+		t.Fatalf("missing last record delimiter")
+	}
+	return plain[:len(plain)-1]
+}
+
+// TestEncryptRFC8291 以 RFC 8291 附录 A 的测试向量校验密钥派生与加密，不依赖 decrypt
+func TestEncryptRFC8291(t *testing.T) {
+	decode := func(s string) []byte {
+		b, err := base64.RawURLEncoding.DecodeString(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	body, err := encryptWith(
+		decode("V2hlbiBJIGdyb3cgdXAsIEkgd2FudCB0byBiZSBhIHdhdGVybWVsb24"),
+		"BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4",
+		"BTBZMqHH6r4Tts7J_aSIgg",
+		decode("DGv6ra1nlYgDCS1FRnbzlw"),
+		decode("yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27ml" +
+		"mlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPT" +
+		"pK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+	if got := base64.RawURLEncoding.EncodeToString(body); got != expected {
+		t.Errorf("unexpected body %s", got)
+	}
+}
+
+func TestWebPushNotify(t *testing.T) {
+	curve := elliptic.P256()
+	uaPrivate, uaX, uaY, err := elliptic.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uaPublic := elliptic.Marshal(curve, uaX, uaY)
+	authSecret := make([]byte, 16)
+	rand.Read(authSecret)
+
+	vapidPrivate, vx, vy, err := elliptic.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vapidPublic := base64.RawURLEncoding.EncodeToString(elliptic.Marshal(curve, vx, vy))
+
+	payload := []byte(`{"title":"Hello","body":"Hello World"}`)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "aes128gcm" || r.Header.Get("TTL") != "60" ||
+			r.Header.Get("Urgency") != "high" || r.Header.Get("Topic") != "news" {
+			t.Errorf("unexpected headers %v", r.Header)
+		}
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "vapid t=") || !strings.HasSuffix(auth, ", k="+vapidPublic) {
+			t.Errorf("unexpected authorization %s", auth)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if plain := decrypt(t, body, uaPrivate, uaPublic, authSecret); !bytes.Equal(plain, payload) {
+			t.Errorf("unexpected payload %s", plain)
+		}
+		w.Header().Set("Location", "/m/1")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	wpClient, err := NewWebPushClient(sdk.WebPush{
+		VAPIDPublicKey:  vapidPublic,
+		VAPIDPrivateKey: base64.RawURLEncoding.EncodeToString(vapidPrivate),
+		Subscriber:      "mailto:push@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notify, err := wpClient.Notify(context.Background(), &MessageRequest{
+		Subscription: Subscription{
+			Endpoint: server.URL + "/push/abc",
+			Keys: Keys{
+				P256dh: base64.RawURLEncoding.EncodeToString(uaPublic),
+				Auth:   base64.RawURLEncoding.EncodeToString(authSecret),
+			},
+		},
+		Payload: payload,
+		TTL:     60,
+		Urgency: "high",
+		Topic:   "news",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notify.GetResult() != "201" || notify.GetData()["location"] != "/m/1" {
+		t.Errorf("unexpected response %v", notify)
+	}
+}
+
+func TestWebPushExpired(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+		w.Write([]byte("push subscription has unsubscribed or expired"))
+	}))
+	defer server.Close()
+
+	curve := elliptic.P256()
+	vapidPrivate, vx, vy, err := elliptic.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wpClient, err := NewWebPushClient(sdk.WebPush{
+		VAPIDPublicKey:  base64.RawURLEncoding.EncodeToString(elliptic.Marshal(curve, vx, vy)),
+		VAPIDPrivateKey: base64.RawURLEncoding.EncodeToString(vapidPrivate),
+		Subscriber:      "mailto:push@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notify, err := wpClient.Notify(context.Background(), &MessageRequest{
+		Subscription: Subscription{Endpoint: server.URL + "/push/abc"},
+	})
+	if err == nil {
+		t.Fatal("expected error for expired subscription")
+	}
+	mr, ok := notify.(*MessageResponse)
+	if !ok {
+		t.Fatalf("expected *MessageResponse, got %v", notify)
+	}
+	if mr.Status != http.StatusGone || !mr.Expired() {
+		t.Errorf("unexpected response %+v", mr)
+	}
+}
+
+func TestFromMessage(t *testing.T) {
+	msg := &sdk.Message{BusinessId: "news", Title: "Hello", Content: "Hello World"}
+	req, err := FromMessage(msg, `{"endpoint":"https://push.example.com/sub","keys":{"p256dh":"key","auth":"secret"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Subscription.Endpoint != "https://push.example.com/sub" || req.Subscription.Keys.P256dh != "key" ||
+		req.Subscription.Keys.Auth != "secret" || req.Topic != "news" {
+		t.Errorf("unexpected request %+v", req)
+	}
+	var payload sdk.Message
+	if err := json.Unmarshal(req.Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload.Title != "Hello" || payload.Content != "Hello World" {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+
+	msg.BusinessId = "not a valid topic!"
+	if req, err = FromMessage(msg, `{"endpoint":"https://push.example.com/sub"}`); err != nil || req.Topic != "" {
+		t.Errorf("unexpected topic %q %v", req.Topic, err)
+	}
+	if _, err = FromMessage(msg, "not json"); err == nil {
+		t.Error("expected error for invalid subscription")
+	}
+}
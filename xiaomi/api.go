@@ -0,0 +1,62 @@
+package xiaomi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/holicc/push-sdk/http"
+	"net/url"
+)
+
+// CommonResponse 为小米各接口通用的返回字段
+type CommonResponse struct {
+	Result      string `json:"result"`      // "ok" 表示成功
+	Description string `json:"description"` // 对失败原因的解释
+	Code        int    `json:"code"`        // 0表示成功，非0表示失败
+	Info        string `json:"info"`
+	Reason      string `json:"reason"`
+	TraceId     string `json:"trace_id"`
+}
+
+func (r *CommonResponse) err() error {
+	if r.Result == "ok" && r.Code == 0 {
+		return nil
+	}
+	return errors.New(fmt.Sprintf("request failed code %d %s %s", r.Code, r.Description, r.Reason))
+}
+
+type apiResponse interface {
+	err() error
+}
+
+// call 以 key=<AppSecret> 鉴权调用小米接口，GET 请求的参数放在查询串中，其余放在表单中
+func (c *client) call(ctx context.Context, method, api string, params url.Values, out apiResponse) error {
+	req := &http.PushRequest{
+		Method: method,
+		URL:    api,
+		Header: []http.HTTPOption{
+			http.SetHeader("Authorization", fmt.Sprintf("key=%s", c.Mi.AppSecret)),
+		},
+	}
+	if method == "GET" {
+		if len(params) > 0 {
+			req.URL += "?" + params.Encode()
+		}
+	} else {
+		req.Body = []byte(params.Encode())
+		req.Header = append(req.Header, http.SetHeader("Content-Type", "application/x-www-form-urlencoded"))
+	}
+
+	resp, err := c.client.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	if resp.Status != 200 {
+		return errors.New(fmt.Sprintf("response status %v %s", resp.Status, string(resp.Body)))
+	}
+	if err = json.Unmarshal(resp.Body, out); err != nil {
+		return err
+	}
+	return out.err()
+}
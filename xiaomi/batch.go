@@ -0,0 +1,98 @@
+package xiaomi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const (
+	maxRegIdsPerRequest     = 1000
+	defaultBatchConcurrency = 4
+)
+
+// BatchMessageRequest 向多个 regId 发送相同的消息，发送时按每1000个切分为多个请求
+type BatchMessageRequest struct {
+	MessageRequest
+	RegistrationIds []string
+	Concurrency     int // 同时发送的请求数，0 表示默认的4
+}
+
+type BatchResult struct {
+	RegistrationIds []string         // 本次请求包含的 regId
+	MessageId       string           // 小米返回的消息ID
+	BadRegIds       []string         // 小米判定无效的 regId
+	Response        *MessageResponse // 小米的原始响应
+	Err             error
+}
+
+// NotifyBatch 将 regId 列表切分后以有限并发发送，每个切片对应一个结果，结果顺序与切片顺序一致
+func (c *client) NotifyBatch(ctx context.Context, req *BatchMessageRequest) ([]BatchResult, error) {
+	if len(req.RegistrationIds) == 0 {
+		return nil, errors.New("message registration ids is empty")
+	}
+	concurrency := req.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+
+	chunks := chunkRegIds(req.RegistrationIds, maxRegIdsPerRequest)
+	results := make([]BatchResult, len(chunks))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		results[i].RegistrationIds = chunk
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+			continue
+		}
+		wg.Add(1)
+		go func(result *BatchResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			c.sendChunk(ctx, &req.MessageRequest, result)
+		}(&results[i])
+	}
+	wg.Wait()
+
+	return results, nil
+}
+
+func (c *client) sendChunk(ctx context.Context, msg *MessageRequest, result *BatchResult) {
+	chunkReq := *msg
+	chunkReq.RegistrationId = strings.Join(result.RegistrationIds, ",")
+	chunkReq.Target = nil
+
+	resp, err := c.Notify(ctx, &chunkReq)
+	if err != nil {
+		result.Err = err
+		return
+	}
+	r := resp.(*MessageResponse)
+	result.Response = r
+	if r.Result != "ok" {
+		result.Err = errors.New(fmt.Sprintf("notify failed code %d %s", r.Code, r.Description))
+		return
+	}
+	result.MessageId = r.Data["id"]
+	if bad := r.Data["bad_regids"]; bad != "" {
+		result.BadRegIds = strings.Split(bad, ",")
+	}
+}
+
+func chunkRegIds(regIds []string, size int) [][]string {
+	chunks := make([][]string, 0, (len(regIds)+size-1)/size)
+	for start := 0; start < len(regIds); start += size {
+		end := start + size
+		if end > len(regIds) {
+			end = len(regIds)
+		}
+		chunks = append(chunks, regIds[start:end])
+	}
+	return chunks
+}
@@ -0,0 +1,98 @@
+package xiaomi
+
+import (
+	"errors"
+	sdk "github.com/holicc/push-sdk"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+const (
+	NotifyEffectLauncher = "1" // 打开应用的 Launcher Activity
+	NotifyEffectActivity = "2" // 打开 IntentUri 指定的应用内页面
+	NotifyEffectWeb      = "3" // 打开 WebUri 指定的网页
+
+	NotificationStyleBigText    = "1" // 大文本样式
+	NotificationStyleBigPicture = "2" // 大图样式
+
+	soundUriPrefix = "android.resource://"
+)
+
+// extraFields 按 sdk.Extra 的 json 标签返回非空的 extra.* 表单字段
+func extraFields(e *sdk.Extra) map[string]string {
+	fields := map[string]string{}
+	if e == nil {
+		return fields
+	}
+	v := reflect.ValueOf(e).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if value := v.Field(i).String(); value != "" {
+			fields[t.Field(i).Tag.Get("json")] = value
+		}
+	}
+	return fields
+}
+
+// extras 合并 Extra 与 TypedExtra，同名键以 TypedExtra 为准
+func (p *MessageRequest) extras() map[string]string {
+	merged := make(map[string]string, len(p.Extra))
+	for k, v := range p.Extra {
+		merged[k] = v
+	}
+	for k, v := range extraFields(p.TypedExtra) {
+		merged[k] = v
+	}
+	return merged
+}
+
+// validateExtras 检查最终发送的 extra.* 字段之间是否一致
+func validateExtras(extra map[string]string) error {
+	effect := extra["extra.notify_effect"]
+	switch effect {
+	case "", NotifyEffectLauncher, NotifyEffectActivity, NotifyEffectWeb:
+	default:
+		return errors.New("wrong notify effect")
+	}
+	if extra["extra.intent_uri"] != "" && effect != NotifyEffectActivity {
+		return errors.New("intent uri requires notify effect 2")
+	}
+	if effect == NotifyEffectActivity && extra["extra.intent_uri"] == "" {
+		return errors.New("notify effect 2 requires intent uri")
+	}
+	if webUri := extra["extra.web_uri"]; webUri != "" {
+		if effect != NotifyEffectWeb {
+			return errors.New("web uri requires notify effect 3")
+		}
+		if u, err := url.Parse(webUri); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return errors.New("web uri must be a http or https url")
+		}
+	} else if effect == NotifyEffectWeb {
+		return errors.New("notify effect 3 requires web uri")
+	}
+	if extra["extra.channel_name"] != "" && extra["extra.channel_id"] == "" {
+		return errors.New("channel name requires channel id")
+	}
+	if extra["extra.channel_id"] != "" && extra["extra.channel_name"] == "" {
+		return errors.New("channel id requires channel name")
+	}
+	if soundUri := extra["extra.sound_uri"]; soundUri != "" && !strings.HasPrefix(soundUri, soundUriPrefix) {
+		return errors.New("sound uri must start with android.resource://")
+	}
+	switch extra["extra.notify_foreground"] {
+	case "", "0", "1":
+	default:
+		return errors.New("notify foreground must be 0 or 1")
+	}
+	style := extra["extra.notification_style_type"]
+	switch style {
+	case "", NotificationStyleBigText, NotificationStyleBigPicture:
+	default:
+		return errors.New("wrong notification style type")
+	}
+	if (style == NotificationStyleBigPicture) != (extra["extra.notification_bigPic_uri"] != "") {
+		return errors.New("big picture style requires big picture uri and vice versa")
+	}
+	return nil
+}
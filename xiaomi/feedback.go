@@ -0,0 +1,70 @@
+package xiaomi
+
+import (
+	"context"
+	"strings"
+)
+
+const (
+	invalidRegIdsPath  = "/v1/feedback/fetch_invalid_regids"
+	invalidAliasesPath = "/v1/feedback/fetch_invalid_aliases"
+	maxFeedbackPolls   = 1000 // 单次轮询最多拉取的批数，避免队列持续写入时无法返回
+)
+
+// FetchInvalidRegIds 拉取一批已失效（如应用被卸载）的 regId，小米返回后即从反馈队列中移除
+func (c *client) FetchInvalidRegIds(ctx context.Context) ([]string, error) {
+	return c.fetchFeedback(ctx, invalidRegIdsPath)
+}
+
+// FetchInvalidAliases 拉取一批已失效的别名，小米返回后即从反馈队列中移除
+func (c *client) FetchInvalidAliases(ctx context.Context) ([]string, error) {
+	return c.fetchFeedback(ctx, invalidAliasesPath)
+}
+
+// PollInvalidRegIds 持续拉取失效的 regId 直到队列为空，每批调用一次 fn；fn 返回错误时停止拉取
+func (c *client) PollInvalidRegIds(ctx context.Context, fn func(regIds []string) error) error {
+	return c.pollFeedback(ctx, invalidRegIdsPath, fn)
+}
+
+// PollInvalidAliases 持续拉取失效的别名直到队列为空，每批调用一次 fn；fn 返回错误时停止拉取
+func (c *client) PollInvalidAliases(ctx context.Context, fn func(aliases []string) error) error {
+	return c.pollFeedback(ctx, invalidAliasesPath, fn)
+}
+
+func (c *client) pollFeedback(ctx context.Context, path string, fn func([]string) error) error {
+	for i := 0; i < maxFeedbackPolls; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		list, err := c.fetchFeedback(ctx, path)
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		if err = fn(list); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *client) fetchFeedback(ctx context.Context, path string) ([]string, error) {
+	var r ListResponse
+	if err := c.call(ctx, "GET", c.feedbackEndpoint(path), nil, &r); err != nil {
+		return nil, err
+	}
+	list := r.Data.List[:0]
+	for _, v := range r.Data.List {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list, nil
+}
+
+// feedbackEndpoint 以 FeedbackHost 拼接反馈接口路径
+func (c *client) feedbackEndpoint(path string) string {
+	return strings.TrimSuffix(c.Mi.FeedbackHost, "/") + path
+}
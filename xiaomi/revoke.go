@@ -0,0 +1,43 @@
+package xiaomi
+
+import (
+	"context"
+	"errors"
+	"net/url"
+)
+
+const revokePath = "/v1/message/revoke"
+
+// RevokeResponse 为撤回消息的结果
+type RevokeResponse struct {
+	CommonResponse
+	Data struct {
+		Id string `json:"id"` // 撤回操作的ID
+	} `json:"data"`
+}
+
+// Revoke 撤回已发送但用户尚未打开的通知，msgId 为发送时 MessageResponse.Data["id"]，
+// target 须与原消息的推送目标一致
+func (c *client) Revoke(ctx context.Context, msgId string, target *Target) (*RevokeResponse, error) {
+	if msgId == "" {
+		return nil, errors.New("message id is empty")
+	}
+	if target == nil {
+		return nil, errors.New("revoke target is empty")
+	}
+	if err := target.validate(); err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Add("msg_id", msgId)
+	params.Add("restricted_package_name", c.Mi.AppPkgName)
+	for k, v := range target.fields() {
+		params.Add(k, v)
+	}
+
+	r := &RevokeResponse{}
+	if err := c.call(ctx, "POST", c.endpoint(revokePath), params, r); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
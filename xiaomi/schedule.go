@@ -0,0 +1,64 @@
+package xiaomi
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+)
+
+const (
+	jobExistPath       = "/v2/schedule_job/exist"
+	jobDeletePath      = "/v2/schedule_job/delete"
+	jobDeleteByKeyPath = "/v3/schedule_job/delete"
+
+	maxScheduleAhead = 7 * 24 * time.Hour
+
+	// CodeJobNotFound 为小米在定时任务不存在或已发送时返回的错误码
+	CodeJobNotFound = 22003
+)
+
+// JobExists 检查定时消息任务是否存在，jobId 为定时消息发送时返回的 Data["id"]；
+// 仅在小米返回 CodeJobNotFound 时返回 false，鉴权失败、限流等其他错误原样返回
+func (c *client) JobExists(ctx context.Context, jobId string) (bool, error) {
+	if jobId == "" {
+		return false, errors.New("job id is empty")
+	}
+	params := url.Values{}
+	params.Add("job_id", jobId)
+
+	var r CommonResponse
+	err := c.call(ctx, "POST", c.endpoint(jobExistPath), params, &r)
+	if err != nil {
+		if r.Code == CodeJobNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// DeleteJob 删除尚未发送的定时消息任务
+func (c *client) DeleteJob(ctx context.Context, jobId string) error {
+	if jobId == "" {
+		return errors.New("job id is empty")
+	}
+	params := url.Values{}
+	params.Add("job_id", jobId)
+
+	var r CommonResponse
+	return c.call(ctx, "POST", c.endpoint(jobDeletePath), params, &r)
+}
+
+// DeleteJobByKey 按发送时设置的 job_key 删除尚未发送的定时消息任务
+func (c *client) DeleteJobByKey(ctx context.Context, jobKey string) error {
+	if jobKey == "" {
+		return errors.New("job key is empty")
+	}
+	params := url.Values{}
+	params.Add("job_key", jobKey)
+	params.Add("restricted_package_name", c.Mi.AppPkgName)
+
+	var r CommonResponse
+	return c.call(ctx, "POST", c.endpoint(jobDeleteByKeyPath), params, &r)
+}
@@ -0,0 +1,142 @@
+package xiaomi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	traceMessagePath  = "/v1/trace/message/status"
+	traceMessagesPath = "/v1/trace/messages/status"
+
+	statsDateLayout = "20060102"
+)
+
+// DailyCounter 为某一天的消息统计数据
+type DailyCounter struct {
+	Date                  string `json:"date"`                  // 日期，格式 yyyyMMdd
+	SingleRecipients      int64  `json:"singleRecipients"`      // 单推的设备数
+	AliasRecipients       int64  `json:"aliasRecipients"`       // 按别名推送的设备数
+	UserAccountRecipients int64  `json:"userAccountRecipients"` // 按 user_account 推送的设备数
+	RegIdRecipients       int64  `json:"regIdRecipients"`       // 按 regId 推送的设备数
+	BroadcastRecipients   int64  `json:"broadcastRecipients"`   // 广播推送的设备数
+	Received              int64  `json:"received"`              // 送达数
+	Click                 int64  `json:"click"`                 // 点击数
+}
+
+type StatsResponse struct {
+	CommonResponse
+	Data struct {
+		Data []DailyCounter `json:"data"`
+	} `json:"data"`
+}
+
+// MessageStatus 为单条消息的追踪状态
+type MessageStatus struct {
+	Id              string `json:"id"`               // 消息ID
+	MsgType         string `json:"msg_type"`         // 消息类型
+	CreateTime      string `json:"create_time"`      // 创建时间
+	CreateTimestamp int64  `json:"create_timestamp"` // 创建时间，毫秒时间戳
+	TimeToLive      string `json:"time_to_live"`     // 消息有效期
+	Resolved        int64  `json:"resolved"`         // 有效设备数
+	Delivered       int64  `json:"delivered"`        // 送达数
+	DeliveryRate    string `json:"delivery_rate"`    // 送达率
+	Click           int64  `json:"click"`            // 点击数
+	ClickRate       string `json:"click_rate"`       // 点击率
+	RawCounter      int64  `json:"raw_counter"`      // 推送目标数
+}
+
+type traceResponse struct {
+	CommonResponse
+	Data struct {
+		Data json.RawMessage `json:"data"`
+	} `json:"data"`
+}
+
+// GetStats 查询 [start, end] 日期范围内每天的消息统计
+func (c *client) GetStats(ctx context.Context, start, end time.Time) ([]DailyCounter, error) {
+	if end.Before(start) {
+		return nil, errors.New("stats end date is before start date")
+	}
+	params := url.Values{}
+	params.Add("start_date", start.Format(statsDateLayout))
+	params.Add("end_date", end.Format(statsDateLayout))
+	params.Add("restricted_package_name", c.Mi.AppPkgName)
+
+	var r StatsResponse
+	if err := c.call(ctx, "GET", c.override(c.Mi.StatsURL, statsPath), params, &r); err != nil {
+		return nil, err
+	}
+	return r.Data.Data, nil
+}
+
+// GetMessageStatus 按消息ID查询消息的追踪状态
+func (c *client) GetMessageStatus(ctx context.Context, msgId string) (*MessageStatus, error) {
+	if msgId == "" {
+		return nil, errors.New("message id is empty")
+	}
+	params := url.Values{}
+	params.Add("msg_id", msgId)
+	return c.traceOne(ctx, params)
+}
+
+// GetMessageStatusByJobKey 按发送时设置的 job_key 查询消息的追踪状态
+func (c *client) GetMessageStatusByJobKey(ctx context.Context, jobKey string) (*MessageStatus, error) {
+	if jobKey == "" {
+		return nil, errors.New("job key is empty")
+	}
+	params := url.Values{}
+	params.Add("job_key", jobKey)
+	return c.traceOne(ctx, params)
+}
+
+// GetMessagesStatus 查询 [begin, end] 时间范围内发送的消息的追踪状态
+func (c *client) GetMessagesStatus(ctx context.Context, begin, end time.Time) ([]MessageStatus, error) {
+	if end.Before(begin) {
+		return nil, errors.New("trace end time is before begin time")
+	}
+	params := url.Values{}
+	params.Add("begin_time", strconv.FormatInt(begin.UnixNano()/int64(time.Millisecond), 10))
+	params.Add("end_time", strconv.FormatInt(end.UnixNano()/int64(time.Millisecond), 10))
+
+	var r traceResponse
+	if err := c.call(ctx, "GET", c.endpoint(traceMessagesPath), params, &r); err != nil {
+		return nil, err
+	}
+	var list []MessageStatus
+	if err := decodeTraceData(r.Data.Data, &list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (c *client) traceOne(ctx context.Context, params url.Values) (*MessageStatus, error) {
+	var r traceResponse
+	if err := c.call(ctx, "GET", c.endpoint(traceMessagePath), params, &r); err != nil {
+		return nil, err
+	}
+	status := &MessageStatus{}
+	if err := decodeTraceData(r.Data.Data, status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// decodeTraceData 解析追踪接口的 data 字段，小米部分集群会将其作为 JSON 字符串返回
+func decodeTraceData(raw json.RawMessage, out interface{}) error {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil
+	}
+	if raw[0] == '"' {
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return err
+		}
+		raw = json.RawMessage(s)
+	}
+	return json.Unmarshal(raw, out)
+}
@@ -0,0 +1,91 @@
+package xiaomi
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+)
+
+const (
+	subscribeTopicPath          = "/v2/topic/subscribe"
+	unsubscribeTopicPath        = "/v2/topic/unsubscribe"
+	subscribeTopicByAliasPath   = "/v2/topic/subscribe/alias"
+	unsubscribeTopicByAliasPath = "/v2/topic/unsubscribe/alias"
+	aliasListPath               = "/v1/alias/all"
+	topicListPath               = "/v1/topic/all"
+)
+
+type ListResponse struct {
+	CommonResponse
+	Data struct {
+		List []string `json:"list"`
+	} `json:"data"`
+}
+
+// SubscribeTopic 为 regId 订阅主题，超过1000个时分批提交
+func (c *client) SubscribeTopic(ctx context.Context, regIds []string, topic string) error {
+	return c.changeTopic(ctx, subscribeTopicPath, "registration_id", regIds, topic)
+}
+
+// UnsubscribeTopic 为 regId 取消订阅主题，超过1000个时分批提交
+func (c *client) UnsubscribeTopic(ctx context.Context, regIds []string, topic string) error {
+	return c.changeTopic(ctx, unsubscribeTopicPath, "registration_id", regIds, topic)
+}
+
+// SubscribeTopicByAlias 为别名订阅主题，超过1000个时分批提交
+func (c *client) SubscribeTopicByAlias(ctx context.Context, aliases []string, topic string) error {
+	return c.changeTopic(ctx, subscribeTopicByAliasPath, "aliases", aliases, topic)
+}
+
+// UnsubscribeTopicByAlias 为别名取消订阅主题，超过1000个时分批提交
+func (c *client) UnsubscribeTopicByAlias(ctx context.Context, aliases []string, topic string) error {
+	return c.changeTopic(ctx, unsubscribeTopicByAliasPath, "aliases", aliases, topic)
+}
+
+// GetAliases 查询 regId 设置的别名
+func (c *client) GetAliases(ctx context.Context, regId string) ([]string, error) {
+	return c.list(ctx, aliasListPath, regId)
+}
+
+// GetTopics 查询 regId 订阅的主题
+func (c *client) GetTopics(ctx context.Context, regId string) ([]string, error) {
+	return c.list(ctx, topicListPath, regId)
+}
+
+func (c *client) changeTopic(ctx context.Context, path, key string, targets []string, topic string) error {
+	if len(targets) == 0 {
+		return errors.New("subscription targets is empty")
+	}
+	if topic == "" {
+		return errors.New("topic is empty")
+	}
+
+	for _, chunk := range chunkRegIds(targets, maxRegIdsPerRequest) {
+		params := url.Values{}
+		params.Add(key, strings.Join(chunk, ","))
+		params.Add("topic", topic)
+		params.Add("restricted_package_name", c.Mi.AppPkgName)
+
+		var r CommonResponse
+		if err := c.call(ctx, "POST", c.endpoint(path), params, &r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *client) list(ctx context.Context, path, regId string) ([]string, error) {
+	if regId == "" {
+		return nil, errors.New("registration id is empty")
+	}
+	params := url.Values{}
+	params.Add("registration_id", regId)
+	params.Add("restricted_package_name", c.Mi.AppPkgName)
+
+	var r ListResponse
+	if err := c.call(ctx, "GET", c.endpoint(path), params, &r); err != nil {
+		return nil, err
+	}
+	return r.Data.List, nil
+}
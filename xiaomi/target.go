@@ -0,0 +1,134 @@
+package xiaomi
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+)
+
+const (
+	regIdPath       = "/v3/message/regid"
+	aliasPath       = "/v3/message/alias"
+	userAccountPath = "/v2/message/user_account"
+	topicPath       = "/v3/message/topic"
+	multiTopicPath  = "/v3/message/multi_topic"
+	allPath         = "/v3/message/all"
+	statsPath       = "/v1/stats/message/counters"
+
+	maxAliasesPerRequest = 1000
+	maxMultiTopics       = 5
+	multiTopicSeparator  = ";$;"
+)
+
+type TargetType int
+
+const (
+	TargetRegId       TargetType = iota // 按 regId 推送
+	TargetAlias                         // 按别名推送
+	TargetUserAccount                   // 按 user_account 推送
+	TargetTopic                         // 按单个主题推送
+	TargetMultiTopic                    // 按多个主题的集合运算结果推送
+	TargetAll                           // 推送给所有设备
+)
+
+type TopicOp string
+
+const (
+	TopicUnion        TopicOp = "UNION"        // 并集
+	TopicIntersection TopicOp = "INTERSECTION" // 交集
+	TopicExcept       TopicOp = "EXCEPT"       // 差集
+)
+
+// Target 描述消息的推送目标，由客户端据此选择小米对应的接口与表单字段
+type Target struct {
+	Type    TargetType
+	Values  []string // regId、别名、user_account 或主题列表，TargetAll 时为空
+	TopicOp TopicOp  // TargetMultiTopic 时的集合运算方式
+}
+
+func (t *Target) validate() error {
+	switch t.Type {
+	case TargetRegId, TargetAlias, TargetUserAccount:
+		if len(t.Values) == 0 {
+			return errors.New("target values is empty")
+		}
+		if len(t.Values) > maxAliasesPerRequest {
+			return errors.New("too many target values, at most 1000")
+		}
+	case TargetTopic:
+		if len(t.Values) != 1 || t.Values[0] == "" {
+			return errors.New("topic target requires exactly one topic")
+		}
+	case TargetMultiTopic:
+		if len(t.Values) < 2 || len(t.Values) > maxMultiTopics {
+			return errors.New("multi topic target requires 2 to 5 topics")
+		}
+		if t.TopicOp != TopicUnion && t.TopicOp != TopicIntersection && t.TopicOp != TopicExcept {
+			return errors.New("wrong topic operation")
+		}
+	case TargetAll:
+		if len(t.Values) != 0 {
+			return errors.New("broadcast target must not have values")
+		}
+	default:
+		return errors.New("unknown target type")
+	}
+	return nil
+}
+
+// fields 返回目标对应的表单字段
+func (t *Target) fields() map[string]string {
+	switch t.Type {
+	case TargetRegId:
+		return map[string]string{"registration_id": strings.Join(t.Values, ",")}
+	case TargetAlias:
+		return map[string]string{"alias": strings.Join(t.Values, ",")}
+	case TargetUserAccount:
+		return map[string]string{"user_account": strings.Join(t.Values, ",")}
+	case TargetTopic:
+		return map[string]string{"topic": t.Values[0]}
+	case TargetMultiTopic:
+		return map[string]string{
+			"topics":   strings.Join(t.Values, multiTopicSeparator),
+			"topic_op": string(t.TopicOp),
+		}
+	}
+	return nil
+}
+
+// pushURL 返回消息应发送到的接口，未指定目标时沿用配置的 PushURL
+func (c *client) pushURL(t *Target) string {
+	if t == nil {
+		return c.Mi.PushURL
+	}
+	switch t.Type {
+	case TargetAlias:
+		return c.endpoint(aliasPath)
+	case TargetUserAccount:
+		return c.endpoint(userAccountPath)
+	case TargetTopic:
+		return c.endpoint(topicPath)
+	case TargetMultiTopic:
+		return c.endpoint(multiTopicPath)
+	case TargetAll:
+		return c.override(c.Mi.BroadcastURL, allPath)
+	}
+	return c.Mi.PushURL
+}
+
+// override 返回显式配置的接口地址，未配置时以 PushURL 的主机拼接 path
+func (c *client) override(api, path string) string {
+	if api != "" {
+		return api
+	}
+	return c.endpoint(path)
+}
+
+// endpoint 以 PushURL 的主机拼接接口路径，使所有接口与配置的推送地址保持同一集群
+func (c *client) endpoint(path string) string {
+	u, err := url.Parse(c.Mi.PushURL)
+	if err != nil || u.Host == "" {
+		return DefaultHost + path
+	}
+	return u.Scheme + "://" + u.Host + path
+}
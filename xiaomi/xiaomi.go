@@ -9,18 +9,44 @@ import (
 	"github.com/holicc/push-sdk/http"
 	"net/url"
 	"strconv"
+	"time"
 )
 
+const (
+	DefaultHost         = "https://api.xmpush.xiaomi.com"
+	DefaultPushURL      = DefaultHost + regIdPath
+	DefaultFeedbackHost = "https://feedback.xmpush.xiaomi.com"
+
+	RegionChina  = "china"  // 中国大陆
+	RegionGlobal = "global" // 新加坡及其他海外地区
+	RegionEurope = "europe" // 欧洲
+	RegionRussia = "russia" // 俄罗斯
+	RegionIndia  = "india"  // 印度
+)
+
+// regionHosts 为各区域推送集群的主机地址
+var regionHosts = map[string]string{
+	"":           DefaultHost,
+	RegionChina:  DefaultHost,
+	RegionGlobal: "https://api.xmpush.global.xiaomi.com",
+	RegionEurope: "https://fr-api.xmpush.global.xiaomi.com",
+	RegionRussia: "https://ru-api.xmpush.global.xiaomi.com",
+	RegionIndia:  "https://idmb-api.xmpush.global.xiaomi.com",
+}
+
 type MessageRequest struct {
 	Payload               string            `json:"payload"`                 // 消息的内容。（注意：需要对payload字符串做urlencode处理）
-	RestrictedPackageName string            `json:"restricted_package_name"` // App的包名
+	RestrictedPackageName string            `json:"restricted_package_name"` // App的包名，为空时使用客户端配置的 AppPkgName
 	PassThrough           int               `json:"pass_through"`            // 0 表示通知栏消息,1 表示透传消息
 	NotifyType            int               `json:"notify_type"`
 	Expire                int64             `json:"time_to_live"`
+	TimeToSend            int64             `json:"time_to_send"`    // 定时发送的时间，毫秒时间戳，仅支持7天内
 	Title                 string            `json:"title"`           // 通知栏展示的通知的标题
 	Description           string            `json:"description"`     // 通知栏展示的通知的描述
 	RegistrationId        string            `json:"registration_id"` // 根据registration_id，发送消息到指定设备上
-	Extra                 map[string]string `json:"extra"`
+	Extra                 map[string]string `json:"extra"`           // 原始的 extra.* 表单字段
+	TypedExtra            *sdk.Extra        `json:"-"`               // 预定义的 extra 字段，与 Extra 同名时以此为准
+	Target                *Target           `json:"-"`               // 推送目标，为空时按 RegistrationId 推送
 }
 
 type MessageResponse struct {
@@ -37,8 +63,6 @@ type client struct {
 	client *http.HTTPClient
 }
 
-var pkgName = ""
-
 func NewXiaoMiClient(mi sdk.XiaoMi) (*client, error) {
 	if mi.AppPkgName == "" {
 		return nil, errors.New("app pkg-name empty")
@@ -46,25 +70,59 @@ func NewXiaoMiClient(mi sdk.XiaoMi) (*client, error) {
 	if mi.AppSecret == "" {
 		return nil, errors.New("app secret empty")
 	}
+	if _, ok := regionHosts[mi.Region]; !ok {
+		return nil, errors.New(fmt.Sprintf("unknown region %s", mi.Region))
+	}
+
+	SetDefaults(&mi)
 
-	pkgName = mi.AppPkgName
 	return &client{
 		Mi:     mi,
 		client: http.NewHTTPClient(),
 	}, nil
 }
 
+// SetDefaults 为未填写的 PushURL 与 FeedbackHost 填充 Region 对应集群的官方地址，未知区域按中国大陆处理。
+// PushURL 的主机是所有推送与查询接口的基础地址，BroadcastURL、StatsURL 仅在显式填写时覆盖对应接口
+func SetDefaults(mi *sdk.XiaoMi) {
+	host, ok := regionHosts[mi.Region]
+	if !ok {
+		host = DefaultHost
+	}
+	if mi.PushURL == "" {
+		mi.PushURL = host + regIdPath
+	}
+	if mi.FeedbackHost == "" {
+		// 海外集群的反馈接口与推送接口同主机
+		if host == DefaultHost {
+			mi.FeedbackHost = DefaultFeedbackHost
+		} else {
+			mi.FeedbackHost = host
+		}
+	}
+}
+
 func (c *client) Notify(ctx context.Context, req sdk.MessageRequest) (sdk.MessageResponse, error) {
 	if e := req.Validate(); e != nil {
 		return nil, e
 	}
-	data, err := req.GetRequestBody()
-	if err != nil {
-		return nil, err
+	var (
+		data   []byte
+		target *Target
+		err    error
+	)
+	if r, ok := req.(*MessageRequest); ok {
+		data = []byte(r.values(c.Mi.AppPkgName).Encode())
+		target = r.Target
+	} else {
+		data, err = req.GetRequestBody()
+		if err != nil {
+			return nil, err
+		}
 	}
 	resp, err := c.client.Do(ctx, &http.PushRequest{
 		Method: "POST",
-		URL:    c.Mi.PushURL,
+		URL:    c.pushURL(target),
 		Body:   data,
 		Header: []http.HTTPOption{
 			http.SetHeader("Authorization", fmt.Sprintf("key=%s", c.Mi.AppSecret)),
@@ -89,7 +147,11 @@ func (p *MessageRequest) Validate() error {
 	if p.Title == "" {
 		return errors.New("message title is empty")
 	}
-	if p.RegistrationId == "" {
+	if p.Target != nil {
+		if err := p.Target.validate(); err != nil {
+			return err
+		}
+	} else if p.RegistrationId == "" {
 		return errors.New("message registration id is empty")
 	}
 	if p.PassThrough != 0 && p.PassThrough != 1 {
@@ -98,10 +160,29 @@ func (p *MessageRequest) Validate() error {
 	if p.NotifyType != -1 && p.NotifyType != 1 && p.NotifyType != 2 && p.NotifyType != 4 {
 		return errors.New("wrong notify type")
 	}
-	return nil
+	if p.TimeToSend < 0 {
+		return errors.New("time to send must not be negative")
+	}
+	if p.TimeToSend > 0 && time.Unix(0, p.TimeToSend*int64(time.Millisecond)).After(time.Now().Add(maxScheduleAhead)) {
+		return errors.New("time to send must be within 7 days")
+	}
+	return validateExtras(p.extras())
 }
 
+// GetRequestBody 返回表单编码的消息，restricted_package_name 取自 RestrictedPackageName，为空时返回错误；
+// 经客户端发送时不调用此方法，RestrictedPackageName 为空则使用客户端配置的包名
 func (p *MessageRequest) GetRequestBody() ([]byte, error) {
+	if p.RestrictedPackageName == "" {
+		return nil, errors.New("restricted package name is empty")
+	}
+	return []byte(p.values("").Encode()), nil
+}
+
+func (p *MessageRequest) values(defaultPkgName string) url.Values {
+	pkgName := p.RestrictedPackageName
+	if pkgName == "" {
+		pkgName = defaultPkgName
+	}
 	messageMap := map[string]string{
 		"payload":                 url.QueryEscape(p.Payload),
 		"restricted_package_name": pkgName,
@@ -109,16 +190,33 @@ func (p *MessageRequest) GetRequestBody() ([]byte, error) {
 		"title":                   p.Title,
 		"notify_type":             strconv.Itoa(p.NotifyType),
 		"description":             p.Description,
-		"registration_id":         p.RegistrationId,
 	}
-	for k, v := range p.Extra {
+	if p.TimeToSend > 0 {
+		messageMap["time_to_send"] = strconv.FormatInt(p.TimeToSend, 10)
+	}
+	if p.Target != nil {
+		for k, v := range p.Target.fields() {
+			messageMap[k] = v
+		}
+	} else {
+		messageMap["registration_id"] = p.RegistrationId
+	}
+	for k, v := range p.extras() {
 		messageMap[k] = v
 	}
 	data := url.Values{}
 	for key, value := range messageMap {
 		data.Add(key, value)
 	}
-	return []byte(data.Encode()), nil
+	return data
+}
+
+// err 判断小米是否拒绝了推送，result 为 "ok" 且 code 为0时表示成功
+func (p *MessageResponse) err() error {
+	if p.Result == "ok" && p.Code == 0 {
+		return nil
+	}
+	return errors.New(fmt.Sprintf("notify request failed code %d %s %s", p.Code, p.Description, p.Reason))
 }
 
 func (p *MessageResponse) GetResult() string {
@@ -128,3 +226,27 @@ func (p *MessageResponse) GetResult() string {
 func (p *MessageResponse) GetData() map[string]string {
 	return p.Data
 }
+
+// FromMessage 将通用消息转换为发送到指定 regId 的小米通知栏消息，点击打开应用；
+// 自定义参数与回执地址作为 extra 字段发送。小米通知没有副标题，也没有业务ID字段，
+// 因此 SubTitle 与 BusinessId 不被支持
+func FromMessage(msg *sdk.Message, regId string) (*MessageRequest, error) {
+	extra := map[string]string{}
+	if msg.CallBack != "" {
+		extra["extra.callback"] = msg.CallBack
+		extra["extra.callback.param"] = msg.CallbackParam
+	}
+	for k, v := range msg.Extra {
+		extra["extra."+k] = v
+	}
+
+	return &MessageRequest{
+		PassThrough:    0,
+		NotifyType:     -1,
+		Title:          msg.Title,
+		Description:    msg.Content,
+		RegistrationId: regId,
+		Extra:          extra,
+		TypedExtra:     &sdk.Extra{NotifyEffect: NotifyEffectLauncher},
+	}, nil
+}
@@ -4,7 +4,15 @@ import (
 	"context"
 	"fmt"
 	sdk "github.com/holicc/push-sdk"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestXiaomiNotify(t *testing.T) {
@@ -34,3 +42,632 @@ func TestXiaomiNotify(t *testing.T) {
 	}
 	fmt.Println(notify)
 }
+
+func TestFromMessage(t *testing.T) {
+	req, err := FromMessage(&sdk.Message{
+		Title:         "Hello",
+		Content:       "Hello World",
+		Extra:         map[string]string{"orderId": "42"},
+		CallBack:      "https://example.com/callback",
+		CallbackParam: "biz-1",
+	}, "reg-id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if req.RegistrationId != "reg-id" || req.Description != "Hello World" {
+		t.Errorf("unexpected request %v", req)
+	}
+	if req.Extra["extra.orderId"] != "42" || req.Extra["extra.callback"] != "https://example.com/callback" ||
+		req.Extra["extra.callback.param"] != "biz-1" {
+		t.Errorf("unexpected extra %v", req.Extra)
+	}
+}
+
+func TestXiaomiNotifyBatch(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		sizes    []int
+		inFlight int32
+		maxSeen  int32
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxSeen)
+			if n <= m || atomic.CompareAndSwapInt32(&maxSeen, m, n) {
+				break
+			}
+		}
+		if r.Header.Get("Authorization") != "key=secret" {
+			t.Errorf("unexpected authorization %s", r.Header.Get("Authorization"))
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		ids := strings.Split(r.PostForm.Get("registration_id"), ",")
+		mu.Lock()
+		sizes = append(sizes, len(ids))
+		mu.Unlock()
+		w.Write([]byte(`{"result":"ok","code":0,"data":{"id":"msg-` + strconv.Itoa(len(ids)) + `","bad_regids":"` + ids[0] + `"}}`))
+	}))
+	defer server.Close()
+
+	miClient, err := NewXiaoMiClient(sdk.XiaoMi{
+		Platform:   sdk.Platform{PushURL: server.URL + "/v3/message/regid"},
+		AppPkgName: "com.example",
+		AppSecret:  "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	regIds := make([]string, 2500)
+	for i := range regIds {
+		regIds[i] = "reg-" + strconv.Itoa(i)
+	}
+	results, err := miClient.NotifyBatch(context.Background(), &BatchMessageRequest{
+		MessageRequest:  MessageRequest{Title: "Hello", Description: "Hello World", NotifyType: -1},
+		RegistrationIds: regIds,
+		Concurrency:     2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 chunks, got %d", len(results))
+	}
+	for i, want := range []int{1000, 1000, 500} {
+		r := results[i]
+		if r.Err != nil {
+			t.Fatal(r.Err)
+		}
+		if len(r.RegistrationIds) != want || r.MessageId != "msg-"+strconv.Itoa(want) {
+			t.Errorf("unexpected chunk %d: %d ids, message id %s", i, len(r.RegistrationIds), r.MessageId)
+		}
+		if len(r.BadRegIds) != 1 || r.BadRegIds[0] != r.RegistrationIds[0] {
+			t.Errorf("unexpected bad reg ids %v", r.BadRegIds)
+		}
+	}
+	if maxSeen > 2 {
+		t.Errorf("concurrency limit exceeded: %d", maxSeen)
+	}
+}
+
+func TestXiaomiTargets(t *testing.T) {
+	forms := make(map[string]map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		form := make(map[string]string)
+		for k := range r.PostForm {
+			form[k] = r.PostForm.Get(k)
+		}
+		forms[r.URL.Path] = form
+		w.Write([]byte(`{"result":"ok","code":0,"data":{"id":"msg-1"}}`))
+	}))
+	defer server.Close()
+
+	miClient, err := NewXiaoMiClient(sdk.XiaoMi{
+		Platform:   sdk.Platform{PushURL: server.URL + regIdPath},
+		AppPkgName: "com.example",
+		AppSecret:  "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	targets := []*Target{
+		{Type: TargetAlias, Values: []string{"a1", "a2"}},
+		{Type: TargetUserAccount, Values: []string{"u1"}},
+		{Type: TargetTopic, Values: []string{"news"}},
+		{Type: TargetMultiTopic, Values: []string{"news", "sports"}, TopicOp: TopicIntersection},
+		{Type: TargetAll},
+	}
+	for _, target := range targets {
+		_, err := miClient.Notify(context.Background(), &MessageRequest{
+			Title: "Hello", Description: "Hello World", NotifyType: -1, Target: target,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if forms[aliasPath]["alias"] != "a1,a2" {
+		t.Errorf("unexpected alias form %v", forms[aliasPath])
+	}
+	if forms[userAccountPath]["user_account"] != "u1" {
+		t.Errorf("unexpected user account form %v", forms[userAccountPath])
+	}
+	if forms[topicPath]["topic"] != "news" {
+		t.Errorf("unexpected topic form %v", forms[topicPath])
+	}
+	if forms[multiTopicPath]["topics"] != "news;$;sports" || forms[multiTopicPath]["topic_op"] != "INTERSECTION" {
+		t.Errorf("unexpected multi topic form %v", forms[multiTopicPath])
+	}
+	if form, ok := forms[allPath]; !ok || form["registration_id"] != "" {
+		t.Errorf("unexpected broadcast form %v", form)
+	}
+
+	err = (&MessageRequest{Title: "Hello", NotifyType: -1, Target: &Target{Type: TargetMultiTopic, Values: []string{"a"}}}).Validate()
+	if err == nil {
+		t.Error("expected error for single topic in multi topic target")
+	}
+}
+
+func TestXiaomiSubscription(t *testing.T) {
+	var subscribed []string
+	mux := http.NewServeMux()
+	mux.HandleFunc(subscribeTopicPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "key=secret" {
+			t.Errorf("unexpected authorization %s", r.Header.Get("Authorization"))
+		}
+		r.ParseForm()
+		if r.PostForm.Get("topic") != "news" || r.PostForm.Get("restricted_package_name") != "com.example" {
+			t.Errorf("unexpected form %v", r.PostForm)
+		}
+		subscribed = append(subscribed, r.PostForm.Get("registration_id"))
+		w.Write([]byte(`{"result":"ok","code":0}`))
+	})
+	mux.HandleFunc(unsubscribeTopicByAliasPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":"error","code":20301,"description":"invalid topic"}`))
+	})
+	mux.HandleFunc(topicListPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("registration_id") != "reg-1" {
+			t.Errorf("unexpected query %v", r.URL.Query())
+		}
+		w.Write([]byte(`{"result":"ok","code":0,"data":{"list":["news","sports"]}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	miClient, err := NewXiaoMiClient(sdk.XiaoMi{
+		Platform:   sdk.Platform{PushURL: server.URL + regIdPath},
+		AppPkgName: "com.example",
+		AppSecret:  "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if err := miClient.SubscribeTopic(ctx, []string{"reg-1", "reg-2"}, "news"); err != nil {
+		t.Fatal(err)
+	}
+	if len(subscribed) != 1 || subscribed[0] != "reg-1,reg-2" {
+		t.Errorf("unexpected subscribe requests %v", subscribed)
+	}
+	if err := miClient.UnsubscribeTopicByAlias(ctx, []string{"alias-1"}, "news"); err == nil {
+		t.Error("expected error from failed unsubscribe")
+	}
+	topics, err := miClient.GetTopics(ctx, "reg-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(topics) != 2 || topics[1] != "sports" {
+		t.Errorf("unexpected topics %v", topics)
+	}
+}
+
+func TestXiaomiMultipleApps(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		want := map[string]string{
+			"key=secret-a": "com.example.a",
+			"key=secret-b": "com.example.b",
+		}[r.Header.Get("Authorization")]
+		if r.PostForm.Get("registration_id") == "override" {
+			want = "com.example.override"
+		}
+		if got := r.PostForm.Get("restricted_package_name"); got != want {
+			t.Errorf("expected package %s, got %s", want, got)
+		}
+		w.Write([]byte(`{"result":"ok","code":0,"data":{"id":"msg-1"}}`))
+	}))
+	defer server.Close()
+
+	newClient := func(pkg, secret string) *client {
+		c, err := NewXiaoMiClient(sdk.XiaoMi{
+			Platform:   sdk.Platform{PushURL: server.URL + regIdPath},
+			AppPkgName: pkg,
+			AppSecret:  secret,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	clientA := newClient("com.example.a", "secret-a")
+	clientB := newClient("com.example.b", "secret-b")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		for _, c := range []*client{clientA, clientB} {
+			wg.Add(1)
+			go func(c *client) {
+				defer wg.Done()
+				_, err := c.Notify(context.Background(), &MessageRequest{
+					Title: "Hello", Description: "Hello World", NotifyType: -1, RegistrationId: "reg-1",
+				})
+				if err != nil {
+					t.Error(err)
+				}
+			}(c)
+		}
+	}
+	wg.Wait()
+
+	_, err := clientA.Notify(context.Background(), &MessageRequest{
+		Title: "Hello", NotifyType: -1, RegistrationId: "override", RestrictedPackageName: "com.example.override",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestXiaomiSchedule(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(regIdPath, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("time_to_send") == "" {
+			t.Errorf("missing time_to_send in %v", r.PostForm)
+		}
+		w.Write([]byte(`{"result":"ok","code":0,"data":{"id":"job-1"}}`))
+	})
+	mux.HandleFunc(jobExistPath, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("job_id") == "job-1" {
+			w.Write([]byte(`{"result":"ok","code":0}`))
+			return
+		}
+		if r.PostForm.Get("job_id") == "job-2" {
+			w.Write([]byte(`{"result":"error","code":22003,"description":"job not found"}`))
+			return
+		}
+		w.Write([]byte(`{"result":"error","code":21301,"description":"auth failed"}`))
+	})
+	mux.HandleFunc(jobDeletePath, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("job_id") != "job-1" {
+			t.Errorf("unexpected form %v", r.PostForm)
+		}
+		w.Write([]byte(`{"result":"ok","code":0}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	miClient, err := NewXiaoMiClient(sdk.XiaoMi{
+		Platform:   sdk.Platform{PushURL: server.URL + regIdPath},
+		AppPkgName: "com.example",
+		AppSecret:  "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &MessageRequest{
+		Title:          "title",
+		Description:    "description",
+		RegistrationId: "reg-1",
+		NotifyType:     -1,
+		TimeToSend:     time.Now().Add(8*24*time.Hour).UnixNano() / int64(time.Millisecond),
+	}
+	if err := req.Validate(); err == nil {
+		t.Error("expected error for time to send beyond 7 days")
+	}
+	req.TimeToSend = time.Now().Add(time.Hour).UnixNano() / int64(time.Millisecond)
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	resp, err := miClient.Notify(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobId := resp.(*MessageResponse).Data["id"]
+	exists, err := miClient.JobExists(ctx, jobId)
+	if err != nil || !exists {
+		t.Errorf("expected job to exist, got %v %v", exists, err)
+	}
+	exists, err = miClient.JobExists(ctx, "job-2")
+	if err != nil || exists {
+		t.Errorf("expected job not to exist, got %v %v", exists, err)
+	}
+	if _, err = miClient.JobExists(ctx, "job-3"); err == nil {
+		t.Error("expected error for failed check")
+	}
+	if err := miClient.DeleteJob(ctx, jobId); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestXiaomiStatsAndTrace(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/stats/message/counters", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("start_date") != "20240101" || q.Get("end_date") != "20240102" || q.Get("restricted_package_name") != "com.example" {
+			t.Errorf("unexpected query %v", q)
+		}
+		w.Write([]byte(`{"result":"ok","code":0,"data":{"data":[{"date":"20240101","received":10,"click":3},{"date":"20240102","received":5,"click":1}]}}`))
+	})
+	mux.HandleFunc(traceMessagePath, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("msg_id") == "msg-1" {
+			w.Write([]byte(`{"result":"ok","code":0,"data":{"data":{"id":"msg-1","delivered":8,"click":2}}}`))
+			return
+		}
+		w.Write([]byte(`{"result":"ok","code":0,"data":{"data":"{\"id\":\"msg-2\",\"delivered\":4}"}}`))
+	})
+	mux.HandleFunc(traceMessagesPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("begin_time") == "" || r.URL.Query().Get("end_time") == "" {
+			t.Errorf("unexpected query %v", r.URL.Query())
+		}
+		w.Write([]byte(`{"result":"ok","code":0,"data":{"data":[{"id":"msg-1"},{"id":"msg-2"}]}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	miClient, err := NewXiaoMiClient(sdk.XiaoMi{
+		Platform:   sdk.Platform{PushURL: server.URL + regIdPath},
+		AppPkgName: "com.example",
+		AppSecret:  "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)
+
+	counters, err := miClient.GetStats(ctx, day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(counters) != 2 || counters[0].Received != 10 || counters[1].Click != 1 {
+		t.Errorf("unexpected counters %+v", counters)
+	}
+	status, err := miClient.GetMessageStatus(ctx, "msg-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Id != "msg-1" || status.Delivered != 8 || status.Click != 2 {
+		t.Errorf("unexpected status %+v", status)
+	}
+	status, err = miClient.GetMessageStatusByJobKey(ctx, "job-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Id != "msg-2" || status.Delivered != 4 {
+		t.Errorf("unexpected status %+v", status)
+	}
+	list, err := miClient.GetMessagesStatus(ctx, day, day.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[1].Id != "msg-2" {
+		t.Errorf("unexpected status list %+v", list)
+	}
+}
+
+func TestXiaomiFeedback(t *testing.T) {
+	pages := [][]string{{"reg-1", "reg-2"}, {"reg-3"}, {}}
+	var polls int32
+	mux := http.NewServeMux()
+	mux.HandleFunc(invalidRegIdsPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "key=secret" {
+			t.Errorf("unexpected authorization %s", r.Header.Get("Authorization"))
+		}
+		page := pages[atomic.AddInt32(&polls, 1)-1]
+		w.Write([]byte(fmt.Sprintf(`{"result":"ok","code":0,"data":{"list":["%s"]}}`, strings.Join(page, `","`))))
+	})
+	mux.HandleFunc(invalidAliasesPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":"error","code":22000,"description":"too frequent"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	miClient, err := NewXiaoMiClient(sdk.XiaoMi{
+		FeedbackHost: server.URL,
+		AppPkgName:   "com.example",
+		AppSecret:    "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	var invalid []string
+	err = miClient.PollInvalidRegIds(ctx, func(regIds []string) error {
+		invalid = append(invalid, regIds...)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(invalid, ",") != "reg-1,reg-2,reg-3" || polls != 3 {
+		t.Errorf("unexpected invalid reg ids %v after %d polls", invalid, polls)
+	}
+	if err := miClient.PollInvalidAliases(ctx, func([]string) error { return nil }); err == nil {
+		t.Error("expected error from failed feedback fetch")
+	}
+}
+
+func TestXiaomiTypedExtra(t *testing.T) {
+	req := &MessageRequest{
+		Title:          "title",
+		RegistrationId: "reg-1",
+		NotifyType:     -1,
+		Extra:          map[string]string{"extra.notify_effect": NotifyEffectLauncher, "extra.orderId": "42"},
+		TypedExtra: &sdk.Extra{
+			NotifyEffect: NotifyEffectActivity,
+			IntentUri:    "intent:#Intent;component=com.example/.MainActivity;end",
+			ChannelId:    "news",
+			ChannelName:  "News",
+			SoundUri:     "android.resource://com.example/raw/beep",
+		},
+	}
+	if err := req.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := req.GetRequestBody(); err == nil {
+		t.Error("expected error for empty restricted package name")
+	}
+	req.RestrictedPackageName = "com.example"
+	body, err := req.GetRequestBody()
+	if err != nil {
+		t.Fatal(err)
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form.Get("extra.notify_effect") != NotifyEffectActivity || form.Get("extra.channel_id") != "news" ||
+		form.Get("extra.orderId") != "42" || form.Get("extra.web_uri") != "" ||
+		form.Get("restricted_package_name") != "com.example" {
+		t.Errorf("unexpected form %v", form)
+	}
+
+	invalid := []sdk.Extra{
+		{NotifyEffect: "4"},
+		{NotifyEffect: NotifyEffectLauncher, IntentUri: "intent:#Intent;end"},
+		{NotifyEffect: NotifyEffectActivity},
+		{NotifyEffect: NotifyEffectWeb},
+		{NotifyEffect: NotifyEffectWeb, WebUri: "ftp://example.com"},
+		{WebUri: "https://example.com"},
+		{ChannelId: "news"},
+		{SoundUri: "/sdcard/beep.mp3"},
+		{NotifyForeground: "2"},
+		{NotificationStyleType: NotificationStyleBigPicture},
+		{NotificationBigPicUri: "https://example.com/a.png"},
+	}
+	for _, extra := range invalid {
+		extra := extra
+		req.Extra = nil
+		req.TypedExtra = &extra
+		if err := req.Validate(); err == nil {
+			t.Errorf("expected error for extra %+v", extra)
+		}
+	}
+}
+
+func TestXiaomiRegion(t *testing.T) {
+	cases := map[string]string{
+		"":           "https://api.xmpush.xiaomi.com",
+		RegionChina:  "https://api.xmpush.xiaomi.com",
+		RegionGlobal: "https://api.xmpush.global.xiaomi.com",
+		RegionEurope: "https://fr-api.xmpush.global.xiaomi.com",
+		RegionRussia: "https://ru-api.xmpush.global.xiaomi.com",
+		RegionIndia:  "https://idmb-api.xmpush.global.xiaomi.com",
+	}
+	for region, host := range cases {
+		miClient, err := NewXiaoMiClient(sdk.XiaoMi{Region: region, AppPkgName: "com.example", AppSecret: "secret"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if miClient.Mi.PushURL != host+regIdPath || miClient.pushURL(&Target{Type: TargetAll}) != host+allPath ||
+			miClient.override(miClient.Mi.StatsURL, statsPath) != host+statsPath || miClient.endpoint(topicPath) != host+topicPath {
+			t.Errorf("unexpected urls for region %q: %+v", region, miClient.Mi.Platform)
+		}
+		if region != "" && region != RegionChina && miClient.Mi.FeedbackHost != host {
+			t.Errorf("unexpected feedback url for region %q: %s", region, miClient.Mi.FeedbackHost)
+		}
+	}
+
+	// 覆盖 PushURL 后所有接口都发往同一主机
+	var paths []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		paths = append(paths, r.URL.Path)
+		mu.Unlock()
+		w.Write([]byte(`{"result":"ok","code":0,"data":{"id":"msg-1"}}`))
+	}))
+	defer server.Close()
+	miClient, err := NewXiaoMiClient(sdk.XiaoMi{
+		Platform:   sdk.Platform{PushURL: server.URL + regIdPath},
+		Region:     RegionEurope,
+		AppPkgName: "com.example",
+		AppSecret:  "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, target := range []*Target{nil, {Type: TargetTopic, Values: []string{"news"}}, {Type: TargetAll}} {
+		req := &MessageRequest{Title: "Hello", NotifyType: -1, RegistrationId: "reg-1", Target: target}
+		if _, err := miClient.Notify(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	batch := &BatchMessageRequest{
+		MessageRequest:  MessageRequest{Title: "Hello", NotifyType: -1},
+		RegistrationIds: []string{"reg-1", "reg-2"},
+	}
+	if _, err := miClient.NotifyBatch(ctx, batch); err != nil {
+		t.Fatal(err)
+	}
+	day := time.Now()
+	if _, err := miClient.GetStats(ctx, day, day); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := miClient.GetMessageStatus(ctx, "msg-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := miClient.Revoke(ctx, "msg-1", &Target{Type: TargetAll}); err != nil {
+		t.Fatal(err)
+	}
+	if err := miClient.SubscribeTopic(ctx, []string{"reg-1"}, "news"); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{regIdPath, topicPath, allPath, regIdPath, statsPath, traceMessagePath, revokePath, subscribeTopicPath}
+	if strings.Join(paths, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected requests %v, got %v", expected, paths)
+	}
+	if _, err := NewXiaoMiClient(sdk.XiaoMi{Region: "mars", AppPkgName: "com.example", AppSecret: "secret"}); err == nil {
+		t.Error("expected error for unknown region")
+	}
+}
+
+func TestXiaomiRevoke(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc(revokePath, func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.PostForm.Get("msg_id") != "msg-1" || r.PostForm.Get("alias") != "a1,a2" ||
+			r.PostForm.Get("restricted_package_name") != "com.example" {
+			t.Errorf("unexpected form %v", r.PostForm)
+		}
+		w.Write([]byte(`{"result":"ok","code":0,"trace_id":"trace-1","data":{"id":"revoke-1"}}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	miClient, err := NewXiaoMiClient(sdk.XiaoMi{
+		Platform:   sdk.Platform{PushURL: server.URL + regIdPath},
+		AppPkgName: "com.example",
+		AppSecret:  "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	resp, err := miClient.Revoke(ctx, "msg-1", &Target{Type: TargetAlias, Values: []string{"a1", "a2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data.Id != "revoke-1" || resp.TraceId != "trace-1" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if _, err := miClient.Revoke(ctx, "msg-1", nil); err == nil {
+		t.Error("expected error for empty target")
+	}
+}
+
+func TestXiaomiDriverCheck(t *testing.T) {
+	if err := (driver{}).Check(&MessageResponse{Result: "ok"}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	// 接口调用成功但推送被拒绝时 Notify 不返回错误，由 Check 识别
+	if err := (driver{}).Check(&MessageResponse{Result: "error", Code: 20301, Reason: "invalid regid"}); err == nil {
+		t.Error("expected error for rejected push")
+	}
+}